@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/postgres"
+	gormio "gorm.io/gorm"
+
+	"github.com/krisadabig/supreme-ms-item/config"
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/storage/migrations"
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/storage/pgx"
+)
+
+// openDatabase connects to the configured PostgreSQL database and sizes its
+// connection pool.
+func openDatabase(cfg *config.Config) (*gormio.DB, error) {
+	// The simple protocol keeps working behind transaction poolers such as
+	// PgBouncer, which managed Postgres offerings put in front of the server.
+	db, err := gormio.Open(postgres.New(postgres.Config{
+		DSN:                  cfg.Database.ConnectionString(),
+		PreferSimpleProtocol: true,
+	}), &gormio.Config{
+		PrepareStmt: false,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.Database.ConnMaxIdleTime)
+	return db, nil
+}
+
+// openPool connects a pgx pool to the configured PostgreSQL database. The
+// database/sql pool settings map to their pgxpool counterparts; pgx keeps no
+// idle connections beyond those it has opened, so max_idle_conns does not
+// apply.
+func openPool(ctx context.Context, cfg *config.Config, tracerProvider trace.TracerProvider) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(cfg.Database.ConnectionString())
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Database.MaxOpenConns > 0 {
+		poolCfg.MaxConns = int32(cfg.Database.MaxOpenConns)
+	}
+	if cfg.Database.ConnMaxLifetime > 0 {
+		poolCfg.MaxConnLifetime = cfg.Database.ConnMaxLifetime
+	}
+	if cfg.Database.ConnMaxIdleTime > 0 {
+		poolCfg.MaxConnIdleTime = cfg.Database.ConnMaxIdleTime
+	}
+	poolCfg.ConnConfig.Tracer = pgx.NewQueryTracer(tracerProvider)
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, err
+	}
+	// Connections are opened lazily; fail now, like GORM, when the
+	// database is unreachable.
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return pool, nil
+}
+
+// openSQLite opens the configured SQLite database file, creating it and its
+// directory when missing.
+func openSQLite(cfg *config.Config) (*gormio.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(cfg.Storage.SQLite.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	// Times are stored as text, so they must all be in the same zone to
+	// sort chronologically.
+	return gormio.Open(sqlite.Open(cfg.Storage.SQLite.ConnectionString()), &gormio.Config{
+		NowFunc: func() time.Time { return time.Now().UTC() },
+	})
+}
+
+// openMigrationTarget connects to the database of the configured SQL
+// storage driver and returns it with its migration dialect.
+func openMigrationTarget(cfg *config.Config) (*gormio.DB, migrations.Dialect, error) {
+	switch cfg.Storage.Driver {
+	case config.DriverPostgres, config.DriverPgx:
+		if err := cfg.Database.Validate(); err != nil {
+			return nil, migrations.Dialect{}, err
+		}
+		db, err := openDatabase(cfg)
+		return db, migrations.Postgres, err
+	case config.DriverSQLite:
+		if err := cfg.Storage.SQLite.Validate(); err != nil {
+			return nil, migrations.Dialect{}, err
+		}
+		db, err := openSQLite(cfg)
+		return db, migrations.SQLite, err
+	default:
+		return nil, migrations.Dialect{}, fmt.Errorf("storage driver %q has no schema", cfg.Storage.Driver)
+	}
+}
+
+// txIsolation maps database.tx_isolation to its database/sql level.
+func txIsolation(cfg *config.Config) sql.IsolationLevel {
+	switch cfg.Database.TxIsolation {
+	case config.IsolationReadCommitted:
+		return sql.LevelReadCommitted
+	case config.IsolationRepeatableRead:
+		return sql.LevelRepeatableRead
+	case config.IsolationSerializable:
+		return sql.LevelSerializable
+	default:
+		return sql.LevelDefault
+	}
+}
@@ -2,94 +2,123 @@ package main
 
 import (
 	"fmt"
-	nethttp "net/http"
 	"os"
+	"time"
 
-	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog"
-	"gorm.io/driver/postgres"
-	gormio "gorm.io/gorm"
 
 	"github.com/krisadabig/supreme-ms-item/config"
-	"github.com/krisadabig/supreme-ms-item/internal/adapters/primary/http"
 	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/logger"
-	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/storage/gorm"
-	"github.com/krisadabig/supreme-ms-item/internal/core/services"
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
 )
 
+const usage = `Usage: item-service [command]
+
+Commands:
+  serve                        Start the HTTP server (default)
+  migrate up                   Apply all pending migrations
+  migrate down [N]             Revert the last N migrations (default 1)
+  migrate status               List migrations and whether they are applied
+  migrate create <name>        Create a new empty migration
+  config print                 Show the effective configuration, its sources and problems
+`
+
 func main() {
-	// Initialize logger first
-	// zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	appEnv := os.Getenv("APP_ENV")
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
 
-	logLevel := zerolog.DebugLevel
+	switch command {
+	case "serve":
+		serve()
+	case "migrate":
+		migrate(args)
+	case "config":
+		configCommand(args)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+}
+
+// logFile is the rotating log file when logging to a file.
+var logFile *logger.RotatingFile
+
+// newLogger creates the bootstrap logger used until the configuration is
+// loaded. Its level is shared with the logger built by configureLogger.
+func newLogger() (ports.Logger, *logger.Level) {
+	logLevel := logger.NewLevel(zerolog.DebugLevel)
 	log := logger.New(
-		logger.WithLevel(logLevel),
+		logger.WithDynamicLevel(logLevel),
 		logger.WithOutput(os.Stdout),
-		// logger.WithPrettyConsole(),
 	)
+	return log, logLevel
+}
 
-	// Log application startup
-	log.Info("starting application initialization")
-	log.With("environment", appEnv).
-		With("log_level", logLevel.String()).
-		Info("loading configuration")
+// timeFormats maps the names accepted by log.time_format to layouts; other
+// values are used as Go time layouts.
+var timeFormats = map[string]string{
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"unix":        zerolog.TimeFormatUnix,
+	"unixms":      zerolog.TimeFormatUnixMs,
+}
 
-	// Load config
-	cfg, err := config.Load()
-	if err != nil {
-		log.Fatal("Failed to load configuration", err)
+// configureLogger builds the application logger from the configuration.
+func configureLogger(cfg *config.Config, logLevel *logger.Level) (ports.Logger, error) {
+	if err := logLevel.SetLevel(cfg.Log.Level); err != nil {
+		return nil, err
 	}
 
-	// Initialize Echo
-	e := echo.New()
-	e.Use(http.CORSMiddleware(cfg.Server.AllowedOrigins))
-	e.Use(http.Logger(log))
-	apiV1 := e.Group("/api/v1")
-
-	// Initialize database connection
-	dsn := fmt.Sprintf("postgres://%s:%s@%s:%d/%s",
-		cfg.Database.Username,
-		cfg.Database.Password,
-		cfg.Database.Host,
-		cfg.Database.Port,
-		cfg.Database.DBName,
-		// cfg.Database.SSLMode,
-	)
-
-	// db, err := gormio.Open(postgres.Open(dsn), &gormio.Config{})
-	db, err := gormio.Open(postgres.New(postgres.Config{
-		DSN:                  dsn,
-		PreferSimpleProtocol: true,
-	}), &gormio.Config{
-		PrepareStmt: false,
-	})
-	if err != nil {
-		log.Fatal("Failed to connect to database", err)
+	opts := []logger.Option{logger.WithDynamicLevel(logLevel)}
+
+	switch cfg.Log.Output {
+	case "stderr":
+		opts = append(opts, logger.WithOutput(os.Stderr))
+	case "file":
+		f, err := logger.NewRotatingFile(cfg.Log.File.Path, int64(cfg.Log.File.MaxSizeMB)<<20, cfg.Log.File.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		logFile = f
+		opts = append(opts, logger.WithOutput(f))
+	default:
+		opts = append(opts, logger.WithOutput(os.Stdout))
 	}
 
-	// Initialize application components
-	itemRepo := gorm.NewGormItemRepository(db)
-	itemService := services.NewItemService(itemRepo, log)
-	itemHandler := http.NewItemHandler(itemService, log)
-
-	// Setup routes
-	itemHandler.RegisterRoutes(apiV1)
-
-	// health check
-	e.GET("/ping", func(c echo.Context) error {
-		return c.String(nethttp.StatusOK, "pong")
-	})
+	if cfg.Log.Format == "console" {
+		opts = append(opts, logger.WithConsoleFormat())
+	}
 
-	// Log successful initialization
-	log.With("version", "1.0.0").Info("application initialized successfully")
+	layout, ok := timeFormats[cfg.Log.TimeFormat]
+	if !ok {
+		layout = cfg.Log.TimeFormat
+	}
+	opts = append(opts, logger.WithTimeFormat(layout))
+
+	if len(cfg.Log.Sampling) > 0 {
+		rates := make(map[zerolog.Level]uint32, len(cfg.Log.Sampling))
+		for name, n := range cfg.Log.Sampling {
+			level, err := zerolog.ParseLevel(name)
+			if err != nil {
+				return nil, err
+			}
+			rates[level] = n
+		}
+		opts = append(opts, logger.WithSampling(rates))
+	}
 
-	// Start server
-	addr := cfg.Server.Port
-	log.With("address", addr).Info("starting http server")
+	return logger.New(opts...), nil
+}
 
-	if err := e.Start(addr); err != nil {
-		log.Fatal("Failed to start server", err)
+// flushLogOutput flushes the log output before the process exits.
+func flushLogOutput() {
+	_ = os.Stdout.Sync()
+	if logFile != nil {
+		_ = logFile.Sync()
+		_ = logFile.Close()
 	}
-
 }
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/krisadabig/supreme-ms-item/config"
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/storage/migrations"
+)
+
+// migrate runs the "migrate" subcommand.
+func migrate(args []string) {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	log, _ := newLogger()
+
+	// create only writes files and needs neither configuration nor a database.
+	if args[0] == "create" {
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "usage: migrate create <name>")
+			os.Exit(2)
+		}
+		paths, err := migrations.Create(migrations.SourceDir, migrations.Dialects, args[1])
+		if err != nil {
+			log.Fatal("Failed to create migration", err)
+		}
+		for _, path := range paths {
+			fmt.Println("created", path)
+		}
+		return
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Failed to load configuration", err)
+	}
+	db, dialect, err := openMigrationTarget(cfg)
+	if err != nil {
+		log.Fatal("Failed to open database", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatal("Failed to access database handle", err)
+	}
+	defer sqlDB.Close()
+
+	migrator, err := migrations.New(sqlDB, dialect, log)
+	if err != nil {
+		log.Fatal("Failed to load migrations", err)
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			log.Fatal("Failed to apply migrations", err)
+		}
+		log.With("applied", len(applied)).Info("database schema is up to date")
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				fmt.Fprintln(os.Stderr, "usage: migrate down [N], N >= 1")
+				os.Exit(2)
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			log.Fatal("Failed to revert migrations", err)
+		}
+		log.With("reverted", len(reverted)).Info("migrations reverted")
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatal("Failed to read migration status", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		w.Flush()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown migrate command %q\n\n%s", args[0], usage)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"errors"
+	nethttp "net/http"
+	"os"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/propagation"
+
+	"github.com/krisadabig/supreme-ms-item/config"
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/primary/http"
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/auth"
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/metrics"
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/tracing"
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+	"github.com/krisadabig/supreme-ms-item/internal/core/services"
+	"github.com/krisadabig/supreme-ms-item/internal/lifecycle"
+)
+
+// serve runs the HTTP server.
+func serve() {
+	// Initialize logger first
+	appEnv := os.Getenv("APP_ENV")
+	log, logLevel := newLogger()
+
+	// Log application startup
+	log.Info("starting application initialization")
+	log.With("environment", appEnv).
+		With("log_level", logLevel.String()).
+		Info("loading configuration")
+
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Failed to load configuration", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatal("Invalid configuration", err)
+	}
+
+	// Switch to the configured logger
+	configured, err := configureLogger(cfg, logLevel)
+	if err != nil {
+		log.Fatal("Failed to configure logger", err)
+	}
+	log = configured
+
+	// Initialize token verification
+	verifier, err := auth.NewJWTVerifier(context.Background(), auth.Config{
+		HS256Secret:         cfg.Auth.HS256Secret,
+		JWKSFile:            cfg.Auth.JWKSFile,
+		JWKSURL:             cfg.Auth.JWKSURL,
+		JWKSRefreshInterval: cfg.Auth.JWKSRefreshInterval,
+		Issuer:              cfg.Auth.Issuer,
+		Audience:            cfg.Auth.Audience,
+		Leeway:              cfg.Auth.Leeway,
+		RolesClaim:          cfg.Auth.RolesClaim,
+		AdminSubjects:       cfg.Auth.AdminUsers,
+	})
+	if err != nil {
+		log.Fatal("Failed to initialize authentication", err)
+	}
+
+	// Initialize metrics
+	var prom *metrics.Prometheus
+	if cfg.Metrics.Enabled {
+		prom = metrics.NewPrometheus("item_service")
+	}
+
+	// Initialize tracing
+	tracerProvider, shutdownTracing, err := tracing.NewProvider(context.Background(), tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		File:        cfg.Tracing.File,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		log.Fatal("Failed to initialize tracing", err)
+	}
+
+	// Initialize Echo
+	e := echo.New()
+	e.HTTPErrorHandler = http.ErrorHandler(log)
+	if e.IPExtractor, err = http.IPExtractor(cfg.Server.TrustedProxies); err != nil {
+		log.Fatal("Invalid trusted proxies", err)
+	}
+	if prom != nil {
+		e.Use(http.Metrics(prom))
+	}
+	e.Use(http.Tracing(tracerProvider, propagation.TraceContext{}))
+	e.Use(http.Recover(log))
+	allowedOrigins := http.NewAllowedOrigins(cfg.Server.AllowedOrigins)
+	e.Use(http.CORSMiddleware(allowedOrigins))
+	e.Use(http.Logger(log, http.LoggerConfig{
+		Body:         http.BodyLogMode(cfg.Log.HTTP.Body),
+		MaxBodyBytes: cfg.Log.HTTP.MaxBodyBytes,
+		RedactFields: cfg.Log.HTTP.RedactFields,
+		ExcludePaths: cfg.Log.HTTP.ExcludePaths,
+	}))
+	rateLimiter := http.NewRateLimiter(cfg.RateLimit.Enabled, cfg.RateLimit.RequestsPerSecond, cfg.RateLimit.Burst)
+	apiV1 := e.Group("/api/v1", http.RateLimit(rateLimiter), http.Auth(verifier, log))
+
+	// Initialize storage
+	store, err := openStorage(context.Background(), cfg, log, tracerProvider, prom)
+	if err != nil {
+		log.Fatal("Failed to open storage", err)
+	}
+
+	// Initialize application components
+	serviceOpts := []services.ItemServiceOption{
+		services.WithTracer(tracing.NewTracer(tracerProvider, "github.com/krisadabig/supreme-ms-item/internal/core/services")),
+		services.WithQueryTimeout(cfg.Database.QueryTimeout),
+		services.WithMaxBatchSize(cfg.Items.MaxBatchSize),
+		services.WithTxManager(store.tx),
+	}
+	if prom != nil {
+		serviceOpts = append(serviceOpts, services.WithMetrics(prom))
+	}
+	itemRepo := store.repo
+	itemService := services.NewItemService(itemRepo, log, serviceOpts...)
+	itemHandler := http.NewItemHandler(itemService, log)
+	itemPurger := services.NewItemPurger(itemRepo, log, cfg.Items.TrashRetention, cfg.Items.PurgeInterval)
+	healthService := services.NewHealthService(log, cfg.Health.CheckTimeout, cfg.Health.CacheTTL)
+	for _, checker := range store.checkers {
+		healthService.Register(checker)
+	}
+	healthHandler := http.NewHealthHandler(healthService)
+	logLevelService := services.NewLogLevelService(logLevel, log)
+	adminHandler := http.NewAdminHandler(logLevelService, log)
+
+	// Setup routes
+	itemHandler.RegisterRoutes(apiV1)
+	adminHandler.RegisterRoutes(apiV1)
+	healthHandler.RegisterRoutes(e)
+	if prom != nil {
+		e.GET(cfg.Metrics.Path, echo.WrapHandler(prom.Handler()))
+	}
+
+	// health check
+	e.GET("/ping", func(c echo.Context) error {
+		return c.String(nethttp.StatusOK, "pong")
+	})
+
+	// Register components; they are stopped in reverse order
+	lc := lifecycle.New(log, cfg.Server.ShutdownTimeout)
+	lc.Append(lifecycle.Hook{
+		Name:   "tracing",
+		OnStop: shutdownTracing,
+	})
+	lc.Append(lifecycle.Hook{
+		Name:   "storage",
+		OnStop: store.close,
+	})
+	configWatcher := config.NewWatcher(log, func(cfg *config.Config, changed []string) {
+		allowedOrigins.Set(cfg.Server.AllowedOrigins)
+		rateLimiter.Configure(cfg.RateLimit.Enabled, cfg.RateLimit.RequestsPerSecond, cfg.RateLimit.Burst)
+		if err := logLevelService.SetConfigured(cfg.Log.Level); err != nil {
+			log.Error("failed to apply log level", err)
+		}
+	})
+	lc.Go("config_watcher", configWatcher.Run)
+	lc.Go("item_purger", func(ctx context.Context) error {
+		itemPurger.Run(ctx)
+		return nil
+	})
+	lc.Append(httpServerHook(e, cfg.Server.Port, log, lc))
+	lc.Append(readinessHook(healthService, cfg.Health.ShutdownDelay, log))
+
+	// Log successful initialization
+	log.With("version", "1.0.0").Info("application initialized successfully")
+
+	if err := lc.Run(context.Background()); err != nil {
+		log.Error("application stopped with error", err)
+		flushLogOutput()
+		os.Exit(1)
+	}
+
+	log.Info("application stopped")
+	flushLogOutput()
+}
+
+// httpServerHook starts Echo in the background and drains in-flight requests
+// on stop. A server that fails to listen shuts the application down.
+func httpServerHook(e *echo.Echo, addr string, log ports.Logger, lc *lifecycle.Lifecycle) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name: "http_server",
+		OnStart: func(context.Context) error {
+			log.With("address", addr).Info("starting http server")
+			go func() {
+				if err := e.Start(addr); err != nil && !errors.Is(err, nethttp.ErrServerClosed) {
+					log.Error("http server failed", err)
+					lc.Shutdown(err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			log.Info("draining http server")
+			return e.Shutdown(ctx)
+		},
+	}
+}
+
+// readinessHook flips /readyz to not ready as the first step of shutdown and
+// waits delay so load balancers stop routing traffic before the server drains.
+func readinessHook(health *services.HealthService, delay time.Duration, log ports.Logger) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name: "readiness",
+		OnStop: func(ctx context.Context) error {
+			health.SetShuttingDown()
+			if delay <= 0 {
+				return nil
+			}
+			log.With("delay", delay.String()).Info("reporting not ready before draining")
+			select {
+			case <-time.After(delay):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
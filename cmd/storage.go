@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"go.opentelemetry.io/otel/trace"
+	gormio "gorm.io/gorm"
+
+	"github.com/krisadabig/supreme-ms-item/config"
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/metrics"
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/storage/gorm"
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/storage/memory"
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/storage/migrations"
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/storage/pgx"
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+// storage is the item store selected by storage.driver.
+type storage struct {
+	repo ports.ItemRepository
+	// tx groups calls to repo in transactions.
+	tx ports.TxManager
+	// checkers report whether the store is reachable, for /readyz.
+	checkers []ports.HealthChecker
+	// close releases the connections of the store.
+	close func(context.Context) error
+}
+
+// openStorage opens the configured item store. SQL stores are migrated when
+// their auto_migrate setting is on and their pools are registered with prom,
+// which may be nil.
+func openStorage(ctx context.Context, cfg *config.Config, log ports.Logger, tracerProvider trace.TracerProvider, prom *metrics.Prometheus) (*storage, error) {
+	switch cfg.Storage.Driver {
+	case config.DriverMemory:
+		log.Warn("items are kept in memory and lost when the service stops")
+		repo := memory.NewMemoryItemRepository()
+		return &storage{
+			repo:  repo,
+			tx:    memory.NewTxManager(repo),
+			close: func(context.Context) error { return nil },
+		}, nil
+	case config.DriverPostgres:
+		db, err := openDatabase(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+		return openSQL(ctx, db, migrations.Postgres, cfg.Database.AutoMigrate, txIsolation(cfg), cfg.Database.DBName, log, tracerProvider, prom)
+	case config.DriverPgx:
+		return openPgx(ctx, cfg, log, tracerProvider, prom)
+	case config.DriverSQLite:
+		db, err := openSQLite(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open database: %w", err)
+		}
+		// SQLite transactions are always serializable.
+		return openSQL(ctx, db, migrations.SQLite, cfg.Storage.SQLite.AutoMigrate, sql.LevelDefault, "sqlite", log, tracerProvider, prom)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+}
+
+// openSQL backs the GORM repository with db, migrating it to the schema of
+// dialect when autoMigrate is set. Transactions run at isolation and name
+// labels the pool metrics.
+func openSQL(ctx context.Context, db *gormio.DB, dialect migrations.Dialect, autoMigrate bool, isolation sql.IsolationLevel, name string, log ports.Logger, tracerProvider trace.TracerProvider, prom *metrics.Prometheus) (*storage, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to access database handle: %w", err)
+	}
+	if err := db.Use(gorm.NewTracingPlugin(tracerProvider)); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to enable database tracing: %w", err)
+	}
+
+	// Apply pending migrations when enabled
+	if autoMigrate {
+		if err := applyMigrations(ctx, sqlDB, dialect, log); err != nil {
+			sqlDB.Close()
+			return nil, err
+		}
+	}
+
+	if prom != nil {
+		if err := prom.RegisterDB(name, sqlDB); err != nil {
+			sqlDB.Close()
+			return nil, fmt.Errorf("failed to register database metrics: %w", err)
+		}
+	}
+
+	return &storage{
+		repo:     gorm.NewGormItemRepository(db),
+		tx:       gorm.NewTxManager(db, isolation),
+		checkers: []ports.HealthChecker{gorm.NewDatabaseChecker(db)},
+		close: func(context.Context) error {
+			return sqlDB.Close()
+		},
+	}, nil
+}
+
+// openPgx backs the pgx repository with a connection pool to PostgreSQL.
+func openPgx(ctx context.Context, cfg *config.Config, log ports.Logger, tracerProvider trace.TracerProvider, prom *metrics.Prometheus) (*storage, error) {
+	pool, err := openPool(ctx, cfg, tracerProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	// Apply pending migrations when enabled. The migrator needs a
+	// database/sql handle, which borrows its connections from the pool.
+	if cfg.Database.AutoMigrate {
+		sqlDB := stdlib.OpenDBFromPool(pool)
+		err := applyMigrations(ctx, sqlDB, migrations.Postgres, log)
+		sqlDB.Close()
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+	}
+
+	if prom != nil {
+		if err := prom.RegisterPgxPool(cfg.Database.DBName, pool); err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("failed to register database metrics: %w", err)
+		}
+	}
+
+	return &storage{
+		repo:     pgx.NewPgxItemRepository(pool),
+		tx:       pgx.NewTxManager(pool, txIsolation(cfg)),
+		checkers: []ports.HealthChecker{pgx.NewDatabaseChecker(pool)},
+		close: func(context.Context) error {
+			pool.Close()
+			return nil
+		},
+	}, nil
+}
+
+// applyMigrations brings the schema of db up to date.
+func applyMigrations(ctx context.Context, db *sql.DB, dialect migrations.Dialect, log ports.Logger) error {
+	migrator, err := migrations.New(db, dialect, log)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	log.With("applied", len(applied)).Info("database schema is up to date")
+	return nil
+}
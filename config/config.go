@@ -1,25 +1,124 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
+
+	"github.com/krisadabig/supreme-ms-item/internal/constants"
 )
 
 type Config struct {
 	Server struct {
-		Port           string   `mapstructure:"port"`
-		AllowedOrigins []string `mapstructure:"allowed_origins"`
+		Port            string        `mapstructure:"port"`
+		AllowedOrigins  []string      `mapstructure:"allowed_origins"`
+		ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+		// TrustedProxies are the CIDRs of proxies whose X-Forwarded-For
+		// names the client. Without them the peer address is the client.
+		TrustedProxies []string `mapstructure:"trusted_proxies"`
 	} `mapstructure:"server"`
-	Database struct {
-		Host     string `mapstructure:"host"`
-		Port     int    `mapstructure:"port"`
-		Username string `mapstructure:"username"`
-		Password string `mapstructure:"password"`
-		DBName   string `mapstructure:"dbname"`
-	} `mapstructure:"database"`
+	Log struct {
+		Level      string `mapstructure:"level"`
+		Format     string `mapstructure:"format"`
+		TimeFormat string `mapstructure:"time_format"`
+		Output     string `mapstructure:"output"`
+		File       struct {
+			Path       string `mapstructure:"path"`
+			MaxSizeMB  int    `mapstructure:"max_size_mb"`
+			MaxBackups int    `mapstructure:"max_backups"`
+		} `mapstructure:"file"`
+		// Sampling keeps one in N messages of a level, e.g. debug: 10.
+		Sampling map[string]uint32 `mapstructure:"sampling"`
+		HTTP     struct {
+			// Body is off, errors or always.
+			Body         string   `mapstructure:"body"`
+			MaxBodyBytes int      `mapstructure:"max_body_bytes"`
+			RedactFields []string `mapstructure:"redact_fields"`
+			ExcludePaths []string `mapstructure:"exclude_paths"`
+		} `mapstructure:"http"`
+	} `mapstructure:"log"`
+	RateLimit struct {
+		Enabled           bool    `mapstructure:"enabled"`
+		RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+		Burst             int     `mapstructure:"burst"`
+	} `mapstructure:"rate_limit"`
+	Storage struct {
+		// Driver is postgres, pgx, sqlite or memory.
+		Driver string       `mapstructure:"driver"`
+		SQLite SQLiteConfig `mapstructure:"sqlite"`
+	} `mapstructure:"storage"`
+	Database DatabaseConfig `mapstructure:"database"`
+	Auth     struct {
+		HS256Secret         string        `mapstructure:"hs256_secret"`
+		HS256SecretFile     string        `mapstructure:"hs256_secret_file"`
+		JWKSFile            string        `mapstructure:"jwks_file"`
+		JWKSURL             string        `mapstructure:"jwks_url"`
+		JWKSRefreshInterval time.Duration `mapstructure:"jwks_refresh_interval"`
+		Issuer              string        `mapstructure:"issuer"`
+		Audience            string        `mapstructure:"audience"`
+		Leeway              time.Duration `mapstructure:"leeway"`
+		RolesClaim          string        `mapstructure:"roles_claim"`
+		AdminUsers          []string      `mapstructure:"admin_users"`
+	} `mapstructure:"auth"`
+	Items struct {
+		TrashRetention time.Duration `mapstructure:"trash_retention"`
+		PurgeInterval  time.Duration `mapstructure:"purge_interval"`
+		// MaxBatchSize is the number of items a batch request may hold.
+		MaxBatchSize int `mapstructure:"max_batch_size"`
+	} `mapstructure:"items"`
+	Health struct {
+		CheckTimeout  time.Duration `mapstructure:"check_timeout"`
+		CacheTTL      time.Duration `mapstructure:"cache_ttl"`
+		ShutdownDelay time.Duration `mapstructure:"shutdown_delay"`
+	} `mapstructure:"health"`
+	Metrics struct {
+		Enabled bool   `mapstructure:"enabled"`
+		Path    string `mapstructure:"path"`
+	} `mapstructure:"metrics"`
+	Tracing struct {
+		Exporter    string  `mapstructure:"exporter"`
+		Endpoint    string  `mapstructure:"endpoint"`
+		Insecure    bool    `mapstructure:"insecure"`
+		File        string  `mapstructure:"file"`
+		ServiceName string  `mapstructure:"service_name"`
+		SampleRatio float64 `mapstructure:"sample_ratio"`
+	} `mapstructure:"tracing"`
+}
+
+// envBinding maps a setting to an environment variable without the APP_ prefix.
+type envBinding struct {
+	key string
+	env string
+}
+
+var envBindings = []envBinding{
+	{"server.port", "PORT"}, // Allow plain PORT too
+	{"server.allowed_origins", "ALLOWED_ORIGINS"},
+	{"storage.driver", "STORAGE_DRIVER"},
+	{"storage.sqlite.path", "SQLITE_PATH"},
+	{"database.dsn", "DATABASE_URL"},
+	{"database.host", "DB_HOST"},
+	{"database.port", "DB_PORT"},
+	{"database.username", "DB_USERNAME"},
+	{"database.password", "DB_PASSWORD"},
+	{"database.password_file", "DB_PASSWORD_FILE"},
+	{"database.dbname", "DB_NAME"},
+	{"database.sslmode", "DB_SSLMODE"},
+	{"database.sslrootcert", "DB_SSLROOTCERT"},
+	{"database.auto_migrate", "DB_AUTO_MIGRATE"},
+	{"log.level", "LOG_LEVEL"},
+	{"log.format", "LOG_FORMAT"},
+	{"auth.hs256_secret", "AUTH_HS256_SECRET"},
+	{"auth.hs256_secret_file", "AUTH_HS256_SECRET_FILE"},
+	{"auth.jwks_file", "AUTH_JWKS_FILE"},
+	{"auth.jwks_url", "AUTH_JWKS_URL"},
+	{"auth.issuer", "AUTH_ISSUER"},
+	{"auth.audience", "AUTH_AUDIENCE"},
+	{"tracing.endpoint", "OTEL_EXPORTER_OTLP_ENDPOINT"},
 }
 
 func Load() (*Config, error) {
@@ -53,20 +152,70 @@ func Load() (*Config, error) {
 	viper.AutomaticEnv()
 	viper.SetEnvPrefix("APP")
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_")) // Convert . to _ for env
-	viper.BindEnv("server.port", "PORT")                   // Allow plain PORT too
-	viper.BindEnv("server.allowed_origins", "ALLOWED_ORIGINS")
-	viper.BindEnv("database.host", "DB_HOST")
-	viper.BindEnv("database.port", "DB_PORT")
-	viper.BindEnv("database.username", "DB_USERNAME")
-	viper.BindEnv("database.password", "DB_PASSWORD")
-	viper.BindEnv("database.dbname", "DB_NAME")
+	for _, b := range envBindings {
+		viper.BindEnv(b.key, b.env)
+	}
+
+	// Defaults
+	viper.SetDefault("server.shutdown_timeout", 15*time.Second)
+	viper.SetDefault("log.level", "debug")
+	viper.SetDefault("log.format", "json")
+	viper.SetDefault("log.time_format", "rfc3339")
+	viper.SetDefault("log.output", "stdout")
+	viper.SetDefault("log.file.max_size_mb", 100)
+	viper.SetDefault("log.file.max_backups", 5)
+	viper.SetDefault("log.http.body", "errors")
+	viper.SetDefault("log.http.max_body_bytes", 2048)
+	viper.SetDefault("log.http.redact_fields", []string{"description", "password", "secret", "token", "access_token", "refresh_token"})
+	viper.SetDefault("log.http.exclude_paths", []string{"/ping", "/healthz", "/readyz"})
+	viper.SetDefault("rate_limit.enabled", false)
+	viper.SetDefault("rate_limit.requests_per_second", 10.0)
+	viper.SetDefault("rate_limit.burst", 20)
+	viper.SetDefault("storage.driver", DriverPostgres)
+	viper.SetDefault("storage.sqlite.path", "data/items.db")
+	viper.SetDefault("storage.sqlite.busy_timeout", 5*time.Second)
+	viper.SetDefault("storage.sqlite.auto_migrate", true)
+	viper.SetDefault("database.port", 5432)
+	viper.SetDefault("database.sslmode", "prefer")
+	viper.SetDefault("database.max_open_conns", 10)
+	viper.SetDefault("database.max_idle_conns", 5)
+	viper.SetDefault("database.conn_max_lifetime", 30*time.Minute)
+	viper.SetDefault("database.conn_max_idle_time", 5*time.Minute)
+	viper.SetDefault("database.query_timeout", time.Duration(constants.DBTimeout)*time.Second)
+	viper.SetDefault("database.tx_isolation", IsolationReadCommitted)
+	viper.SetDefault("auth.jwks_refresh_interval", time.Hour)
+	viper.SetDefault("auth.leeway", 30*time.Second)
+	viper.SetDefault("auth.roles_claim", "roles")
+	viper.SetDefault("items.trash_retention", 30*24*time.Hour)
+	viper.SetDefault("items.purge_interval", time.Hour)
+	viper.SetDefault("items.max_batch_size", 100)
+	viper.SetDefault("health.check_timeout", 2*time.Second)
+	viper.SetDefault("health.cache_ttl", 5*time.Second)
+	viper.SetDefault("metrics.enabled", true)
+	viper.SetDefault("metrics.path", "/metrics")
+	viper.SetDefault("tracing.exporter", "none")
+	viper.SetDefault("tracing.service_name", "item-service")
+	viper.SetDefault("tracing.sample_ratio", 1.0)
 
 	var cfg Config
-	if err := viper.Unmarshal(&cfg); err != nil {
+	err := viper.Unmarshal(&cfg)
+	if err != nil {
 		log.Println("Failed to unmarshal config:", err)
 		return nil, err
 	}
 
+	// Secrets mounted as files take precedence over inline values
+	if cfg.Database.PasswordFile != "" {
+		if cfg.Database.Password, err = readSecretFile(cfg.Database.PasswordFile); err != nil {
+			return nil, fmt.Errorf("database.password_file: %w", err)
+		}
+	}
+	if cfg.Auth.HS256SecretFile != "" {
+		if cfg.Auth.HS256Secret, err = readSecretFile(cfg.Auth.HS256SecretFile); err != nil {
+			return nil, fmt.Errorf("auth.hs256_secret_file: %w", err)
+		}
+	}
+
 	// Handle comma-separated env overrides for allowed origins
 	if raw := viper.GetString("server.allowed_origins"); raw != "" {
 		parts := strings.Split(raw, ",")
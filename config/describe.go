@@ -0,0 +1,157 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Sources a setting can come from, in increasing order of precedence.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+)
+
+const redacted = "[REDACTED]"
+
+// secretKeys are settings whose values must never be printed.
+var secretKeys = []string{"password", "secret", "token", "key"}
+
+// Setting is one effective configuration value and where it came from.
+type Setting struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+// Describe lists every setting loaded by Load, sorted by key, with secrets
+// redacted. Source is the config file, an environment variable (named in
+// parentheses) or the built-in default.
+func Describe() []Setting {
+	keys := viperKeys()
+
+	settings := make([]Setting, 0, len(keys))
+	for _, key := range keys {
+		source := SourceDefault
+		if env, ok := envSource(key); ok {
+			source = SourceEnv + " (" + env + ")"
+		} else if viper.InConfig(key) {
+			source = SourceFile
+		}
+		settings = append(settings, Setting{
+			Key:    key,
+			Value:  redact(key, formatValue(viper.Get(key))),
+			Source: source,
+		})
+	}
+	return settings
+}
+
+// viperKeys returns every loaded key in sorted order.
+func viperKeys() []string {
+	keys := viper.AllKeys()
+	sort.Strings(keys)
+	return keys
+}
+
+// ConfigFile returns the path of the file read by Load.
+func ConfigFile() string {
+	return viper.ConfigFileUsed()
+}
+
+// envSource returns the environment variable that sets key, if any.
+func envSource(key string) (string, bool) {
+	for _, b := range envBindings {
+		if b.key == key {
+			if _, ok := os.LookupEnv(b.env); ok {
+				return b.env, true
+			}
+		}
+	}
+	env := "APP_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+	if _, ok := os.LookupEnv(env); ok {
+		return env, true
+	}
+	return "", false
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = fmt.Sprint(item)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// redact hides secret values. Connection strings keep everything but the
+// password, in the userinfo, the query or a key=value pair.
+func redact(key, value string) string {
+	if value == "" {
+		return value
+	}
+	name := key[strings.LastIndex(key, ".")+1:]
+	if name == "dsn" {
+		return redactDSN(value)
+	}
+	if strings.HasSuffix(name, "_file") {
+		return value
+	}
+	if isSecret(name) {
+		return redacted
+	}
+	return value
+}
+
+func isSecret(name string) bool {
+	for _, secret := range secretKeys {
+		if strings.Contains(strings.ToLower(name), secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// dsnPair matches one key=value pair of a libpq DSN, quoted or not.
+var dsnPair = regexp.MustCompile(`([^\s=]+)(\s*=\s*)('(?:\\.|[^'\\])*'|\S*)`)
+
+func redactDSN(dsn string) string {
+	if !isURLDSN(dsn) {
+		return dsnPair.ReplaceAllStringFunc(dsn, func(pair string) string {
+			m := dsnPair.FindStringSubmatch(pair)
+			if !isSecret(m[1]) {
+				return pair
+			}
+			return m[1] + m[2] + redacted
+		})
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return redacted
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redacted)
+	}
+	query := u.Query()
+	for param := range query {
+		if isSecret(param) {
+			query[param] = []string{redacted}
+		}
+	}
+	u.RawQuery = query.Encode()
+	// Keep the marker readable rather than escaped.
+	return strings.ReplaceAll(u.String(), url.QueryEscape(redacted), redacted)
+}
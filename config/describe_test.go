@@ -0,0 +1,25 @@
+package config
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		key, value, want string
+	}{
+		{"database.password", "hunter2", redacted},
+		{"auth.hs256_secret", "hunter2", redacted},
+		{"database.password_file", "/run/secrets/db", "/run/secrets/db"},
+		{"database.host", "db", "db"},
+		{"database.dsn", "", ""},
+		{"database.dsn", "postgres://app:hunter2@db:5432/items?sslmode=require", "postgres://app:" + redacted + "@db:5432/items?sslmode=require"},
+		{"database.dsn", "postgres://db/items?password=hunter2&user=app", "postgres://db/items?password=" + redacted + "&user=app"},
+		{"database.dsn", "postgresql://app@db/items", "postgresql://app@db/items"},
+		{"database.dsn", "host=db user=app password=hunter2 dbname=items", "host=db user=app password=" + redacted + " dbname=items"},
+		{"database.dsn", "host=db password = 'hunter 2\\' x' sslmode=require", "host=db password = " + redacted + " sslmode=require"},
+	}
+	for _, tt := range tests {
+		if got := redact(tt.key, tt.value); got != tt.want {
+			t.Errorf("redact(%q, %q) = %q, want %q", tt.key, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SQLiteConfig holds the settings of the SQLite storage driver.
+type SQLiteConfig struct {
+	// Path is the database file. It is created, along with its directory,
+	// when missing.
+	Path string `mapstructure:"path"`
+	// BusyTimeout is how long a write waits for another connection to
+	// release the database.
+	BusyTimeout time.Duration `mapstructure:"busy_timeout"`
+	// AutoMigrate applies pending migrations on startup.
+	AutoMigrate bool `mapstructure:"auto_migrate"`
+}
+
+// ConnectionString returns the DSN of the database file. Connections use
+// the write-ahead log so reads do not block the writer, take the write lock
+// when a transaction begins rather than on its first write, and match LIKE
+// case sensitively as PostgreSQL does. The driver stores times in its
+// sortable default format; setting _time_format would make it skip _txlock.
+func (s SQLiteConfig) ConnectionString() string {
+	query := url.Values{}
+	query.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", s.BusyTimeout.Milliseconds()))
+	query.Add("_pragma", "journal_mode(WAL)")
+	query.Add("_pragma", "foreign_keys(1)")
+	query.Add("_pragma", "case_sensitive_like(1)")
+	query.Set("_txlock", "immediate")
+	return s.Path + "?" + query.Encode()
+}
+
+// Validate checks the SQLite settings alone, for commands that only need
+// a connection.
+func (s SQLiteConfig) Validate() error {
+	return s.problems().err()
+}
+
+func (s SQLiteConfig) problems() problems {
+	var p problems
+	switch {
+	case s.Path == "":
+		p.add("storage.sqlite.path", "is required")
+	case s.Path == ":memory:":
+		p.add("storage.sqlite.path", "must be a file, use the memory driver instead")
+	case strings.ContainsAny(s.Path, "?#"):
+		p.add("storage.sqlite.path", "must not contain ? or #")
+	}
+	notNegative(&p, "storage.sqlite.busy_timeout", s.BusyTimeout)
+	return p
+}
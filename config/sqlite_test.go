@@ -0,0 +1,67 @@
+package config
+
+import (
+	"database/sql"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	_ "github.com/glebarez/go-sqlite"
+)
+
+func TestSQLiteValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  SQLiteConfig
+		want []string
+	}{
+		{"valid", SQLiteConfig{Path: "data/items.db", BusyTimeout: time.Second}, nil},
+		{"no path", SQLiteConfig{}, []string{"storage.sqlite.path"}},
+		{"in memory", SQLiteConfig{Path: ":memory:"}, []string{"storage.sqlite.path"}},
+		{"path with a query", SQLiteConfig{Path: "items.db?mode=ro"}, []string{"storage.sqlite.path"}},
+		{"negative busy timeout", SQLiteConfig{Path: "items.db", BusyTimeout: -time.Second}, []string{"storage.sqlite.busy_timeout"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := problemKeys(t, tt.cfg.Validate()); !slices.Equal(got, tt.want) {
+				t.Errorf("Validate reported %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLiteConnectionString(t *testing.T) {
+	cfg := SQLiteConfig{Path: filepath.Join(t.TempDir(), "items.db"), BusyTimeout: 1500 * time.Millisecond}
+	db, err := sql.Open("sqlite", cfg.ConnectionString())
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+
+	pragmas := []struct {
+		name string
+		want string
+	}{
+		{"journal_mode", "wal"},
+		{"busy_timeout", "1500"},
+		{"foreign_keys", "1"},
+	}
+	for _, p := range pragmas {
+		var got string
+		if err := db.QueryRow("PRAGMA " + p.name).Scan(&got); err != nil {
+			t.Fatalf("PRAGMA %s: %v", p.name, err)
+		}
+		if got != p.want {
+			t.Errorf("PRAGMA %s = %s, want %s", p.name, got, p.want)
+		}
+	}
+
+	var matches bool
+	if err := db.QueryRow("SELECT 'Milk' LIKE 'milk'").Scan(&matches); err != nil {
+		t.Fatalf("LIKE: %v", err)
+	}
+	if matches {
+		t.Error("LIKE matched case insensitively")
+	}
+}
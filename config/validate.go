@@ -0,0 +1,269 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError lists every problem found in a configuration.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// problems collects configuration problems keyed by setting.
+type problems []string
+
+func (p *problems) add(key, format string, args ...any) {
+	*p = append(*p, key+": "+fmt.Sprintf(format, args...))
+}
+
+func (p problems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: p}
+}
+
+var (
+	drivers    = []string{DriverPostgres, DriverPgx, DriverSQLite, DriverMemory}
+	sslModes   = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	isolations = []string{IsolationReadCommitted, IsolationRepeatableRead, IsolationSerializable}
+	exporters  = []string{"none", "otlp", "stdout", "file"}
+	logLevels  = []string{"trace", "debug", "info", "warn", "error"}
+	logFormats = []string{"json", "console"}
+	logOutputs = []string{"stdout", "stderr", "file"}
+	logBodies  = []string{"off", "errors", "always"}
+)
+
+// minHS256SecretLen is the size of an HS256 key below which RFC 7518
+// forbids using it.
+const minHS256SecretLen = 32
+
+// Validate checks the whole configuration and reports every problem at once.
+func (c *Config) Validate() error {
+	var p problems
+
+	if err := validateListenAddress(c.Server.Port); err != nil {
+		p.add("server.port", "%v", err)
+	}
+	for _, origin := range c.Server.AllowedOrigins {
+		if err := validateOrigin(origin); err != nil {
+			p.add("server.allowed_origins", "%q: %v", origin, err)
+		}
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil {
+			p.add("server.trusted_proxies", "%q: must be a CIDR, e.g. 10.0.0.0/8", proxy)
+		}
+	}
+	positive(&p, "server.shutdown_timeout", c.Server.ShutdownTimeout)
+
+	if !slices.Contains(logLevels, c.Log.Level) {
+		p.add("log.level", "must be one of %s", strings.Join(logLevels, ", "))
+	}
+	if !slices.Contains(logFormats, c.Log.Format) {
+		p.add("log.format", "must be one of %s", strings.Join(logFormats, ", "))
+	}
+	if c.Log.TimeFormat == "" {
+		p.add("log.time_format", "is required")
+	}
+	if !slices.Contains(logOutputs, c.Log.Output) {
+		p.add("log.output", "must be one of %s", strings.Join(logOutputs, ", "))
+	}
+	if c.Log.Output == "file" && c.Log.File.Path == "" {
+		p.add("log.file.path", "is required when log.output is file")
+	}
+	if c.Log.File.MaxSizeMB < 0 {
+		p.add("log.file.max_size_mb", "must not be negative")
+	}
+	if c.Log.File.MaxBackups < 0 {
+		p.add("log.file.max_backups", "must not be negative")
+	}
+	for level := range c.Log.Sampling {
+		if !slices.Contains(logLevels, level) {
+			p.add("log.sampling", "unknown level %q", level)
+		}
+	}
+	if !slices.Contains(logBodies, c.Log.HTTP.Body) {
+		p.add("log.http.body", "must be one of %s", strings.Join(logBodies, ", "))
+	}
+	if c.Log.HTTP.MaxBodyBytes < 0 {
+		p.add("log.http.max_body_bytes", "must not be negative")
+	}
+	for _, path := range c.Log.HTTP.ExcludePaths {
+		if !strings.HasPrefix(path, "/") {
+			p.add("log.http.exclude_paths", "%q must start with /", path)
+		}
+	}
+
+	if c.RateLimit.Enabled {
+		if c.RateLimit.RequestsPerSecond <= 0 {
+			p.add("rate_limit.requests_per_second", "must be positive")
+		}
+		if c.RateLimit.Burst < 1 {
+			p.add("rate_limit.burst", "must be at least 1")
+		}
+	}
+
+	if !slices.Contains(drivers, c.Storage.Driver) {
+		p.add("storage.driver", "must be one of %s", strings.Join(drivers, ", "))
+	}
+	switch c.Storage.Driver {
+	case DriverPostgres, DriverPgx:
+		p = append(p, c.Database.problems()...)
+	case DriverSQLite:
+		p = append(p, c.Storage.SQLite.problems()...)
+	}
+
+	if c.Auth.HS256Secret == "" && c.Auth.JWKSFile == "" && c.Auth.JWKSURL == "" {
+		p.add("auth", "one of hs256_secret, hs256_secret_file, jwks_file or jwks_url is required")
+	}
+	if c.Auth.HS256Secret != "" && len(c.Auth.HS256Secret) < minHS256SecretLen {
+		p.add("auth.hs256_secret", "must be at least %d bytes", minHS256SecretLen)
+	}
+	if c.Auth.JWKSFile != "" && c.Auth.JWKSURL != "" {
+		p.add("auth", "jwks_file and jwks_url are mutually exclusive")
+	}
+	if c.Auth.JWKSURL != "" {
+		if err := validateURL(c.Auth.JWKSURL); err != nil {
+			p.add("auth.jwks_url", "%v", err)
+		}
+	}
+	notNegative(&p, "auth.jwks_refresh_interval", c.Auth.JWKSRefreshInterval)
+	notNegative(&p, "auth.leeway", c.Auth.Leeway)
+
+	notNegative(&p, "items.trash_retention", c.Items.TrashRetention)
+	notNegative(&p, "items.purge_interval", c.Items.PurgeInterval)
+	if c.Items.MaxBatchSize < 1 {
+		p.add("items.max_batch_size", "must be at least 1")
+	}
+
+	positive(&p, "health.check_timeout", c.Health.CheckTimeout)
+	notNegative(&p, "health.cache_ttl", c.Health.CacheTTL)
+	notNegative(&p, "health.shutdown_delay", c.Health.ShutdownDelay)
+
+	if c.Metrics.Enabled && !strings.HasPrefix(c.Metrics.Path, "/") {
+		p.add("metrics.path", "must start with /")
+	}
+
+	if !slices.Contains(exporters, c.Tracing.Exporter) {
+		p.add("tracing.exporter", "must be one of %s", strings.Join(exporters, ", "))
+	}
+	if c.Tracing.Exporter == "file" && c.Tracing.File == "" {
+		p.add("tracing.file", "is required by the file exporter")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		p.add("tracing.sample_ratio", "must be between 0 and 1")
+	}
+
+	return p.err()
+}
+
+// Validate checks the database settings alone, for commands that only need
+// a connection.
+func (d DatabaseConfig) Validate() error {
+	return d.problems().err()
+}
+
+func (d DatabaseConfig) problems() problems {
+	var p problems
+
+	// A DSN replaces the settings that pick the server.
+	if d.DSN == "" {
+		if d.Host == "" {
+			p.add("database.host", "is required")
+		}
+		if d.Port < 1 || d.Port > 65535 {
+			p.add("database.port", "must be between 1 and 65535")
+		}
+		if d.Username == "" {
+			p.add("database.username", "is required")
+		}
+		if d.DBName == "" {
+			p.add("database.dbname", "is required")
+		}
+	}
+	if d.SSLMode != "" && !slices.Contains(sslModes, d.SSLMode) {
+		p.add("database.sslmode", "must be one of %s", strings.Join(sslModes, ", "))
+	}
+
+	if d.MaxOpenConns < 0 {
+		p.add("database.max_open_conns", "must not be negative")
+	}
+	if d.MaxIdleConns < 0 {
+		p.add("database.max_idle_conns", "must not be negative")
+	}
+	if d.MaxOpenConns > 0 && d.MaxIdleConns > d.MaxOpenConns {
+		p.add("database.max_idle_conns", "must not exceed max_open_conns")
+	}
+	notNegative(&p, "database.conn_max_lifetime", d.ConnMaxLifetime)
+	notNegative(&p, "database.conn_max_idle_time", d.ConnMaxIdleTime)
+	notNegative(&p, "database.statement_timeout", d.StatementTimeout)
+	notNegative(&p, "database.query_timeout", d.QueryTimeout)
+	if d.TxIsolation != "" && !slices.Contains(isolations, d.TxIsolation) {
+		p.add("database.tx_isolation", "must be one of %s", strings.Join(isolations, ", "))
+	}
+
+	return p
+}
+
+// validateListenAddress accepts host:port and :port addresses.
+func validateListenAddress(addr string) error {
+	if addr == "" {
+		return errors.New("is required")
+	}
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return errors.New("must be host:port or :port")
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return errors.New("port must be between 1 and 65535")
+	}
+	return nil
+}
+
+// validateOrigin accepts "*" and scheme://host[:port] origins.
+func validateOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("must be * or an http(s) origin such as https://example.com")
+	}
+	if u.Path != "" && u.Path != "/" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return errors.New("must not contain a path, query, fragment or credentials")
+	}
+	return nil
+}
+
+func validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("must be an http(s) URL")
+	}
+	return nil
+}
+
+func positive(p *problems, key string, d time.Duration) {
+	if d <= 0 {
+		p.add(key, "must be positive")
+	}
+}
+
+func notNegative(p *problems, key string, d time.Duration) {
+	if d < 0 {
+		p.add(key, "must not be negative")
+	}
+}
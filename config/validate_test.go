@@ -0,0 +1,99 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// validConfig returns a configuration that passes Validate.
+func validConfig() *Config {
+	var c Config
+	c.Server.Port = ":8080"
+	c.Server.ShutdownTimeout = 15 * time.Second
+	c.Log.Level = "info"
+	c.Log.Format = "json"
+	c.Log.TimeFormat = "rfc3339"
+	c.Log.Output = "stdout"
+	c.Log.HTTP.Body = "errors"
+	c.Storage.Driver = DriverMemory
+	c.Auth.HS256Secret = strings.Repeat("s", minHS256SecretLen)
+	c.Items.MaxBatchSize = 100
+	c.Health.CheckTimeout = 2 * time.Second
+	c.Tracing.Exporter = "none"
+	return &c
+}
+
+// problemKeys returns the settings err reports problems with.
+func problemKeys(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Validate returned %T, want *ValidationError", err)
+	}
+	keys := make([]string, len(verr.Problems))
+	for i, problem := range verr.Problems {
+		keys[i], _, _ = strings.Cut(problem, ":")
+	}
+	return keys
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(c *Config)
+		want []string
+	}{
+		{"valid", func(c *Config) {}, nil},
+		{"empty port", func(c *Config) { c.Server.Port = "" }, []string{"server.port"}},
+		{"port out of range", func(c *Config) { c.Server.Port = ":70000" }, []string{"server.port"}},
+		{"origins", func(c *Config) {
+			c.Server.AllowedOrigins = []string{"*", "https://app.example", "https://*.example.com", "app.example", "https://app.example/path"}
+		}, []string{"server.allowed_origins", "server.allowed_origins"}},
+		{"every problem at once", func(c *Config) {
+			c.Server.Port = "8080"
+			c.Log.Level = "verbose"
+			c.Storage.Driver = "mysql"
+			c.Tracing.SampleRatio = 2
+		}, []string{"server.port", "log.level", "storage.driver", "tracing.sample_ratio"}},
+		{"short HS256 secret", func(c *Config) { c.Auth.HS256Secret = "dev" }, []string{"auth.hs256_secret"}},
+		{"no signing key", func(c *Config) { c.Auth.HS256Secret = "" }, []string{"auth"}},
+		{"JWKS file and URL", func(c *Config) {
+			c.Auth.JWKSFile, c.Auth.JWKSURL = "jwks.json", "https://idp.example/jwks"
+		}, []string{"auth"}},
+		{"JWKS URL without scheme", func(c *Config) { c.Auth.JWKSURL = "idp.example/jwks" }, []string{"auth.jwks_url"}},
+		{"postgres without a server", func(c *Config) {
+			c.Storage.Driver = DriverPostgres
+			c.Database.Port = 5432
+		}, []string{"database.host", "database.username", "database.dbname"}},
+		{"postgres DSN", func(c *Config) {
+			c.Storage.Driver = DriverPgx
+			c.Database.DSN = "postgres://app@db/items"
+			c.Database.StatementTimeout = time.Second
+		}, nil},
+		{"postgres DSN with negative statement timeout", func(c *Config) {
+			c.Storage.Driver = DriverPostgres
+			c.Database.DSN = "host=db user=app"
+			c.Database.StatementTimeout = -time.Second
+		}, []string{"database.statement_timeout"}},
+		{"unknown sslmode with a DSN", func(c *Config) {
+			c.Storage.Driver = DriverPostgres
+			c.Database.DSN = "postgres://app@db/items"
+			c.Database.SSLMode = "always"
+		}, []string{"database.sslmode"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig()
+			tt.edit(c)
+			if got := problemKeys(t, c.Validate()); !slices.Equal(got, tt.want) {
+				t.Errorf("Validate reported %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
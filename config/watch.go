@@ -0,0 +1,180 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+// reloadable lists the settings, or sections ending in a dot, that may change
+// while the server runs. Changes to any other setting are rejected.
+var reloadable = []string{
+	"server.allowed_origins",
+	"log.level",
+	"rate_limit.",
+}
+
+// reloadDebounce coalesces the burst of events editors emit when saving.
+const reloadDebounce = 200 * time.Millisecond
+
+// ApplyFunc applies a validated configuration whose changed settings are all
+// reloadable. changed lists the keys that differ from the running configuration.
+type ApplyFunc func(cfg *Config, changed []string)
+
+// Watcher reloads the configuration when the config file changes or the
+// process receives SIGHUP.
+type Watcher struct {
+	logger  ports.Logger
+	apply   ApplyFunc
+	current map[string]string
+}
+
+// NewWatcher returns a watcher for the configuration returned by the last
+// call to Load.
+func NewWatcher(logger ports.Logger, apply ApplyFunc) *Watcher {
+	return &Watcher{
+		logger:  logger.With("component", "config_watcher"),
+		apply:   apply,
+		current: snapshot(),
+	}
+}
+
+// Run watches for changes until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) error {
+	file, err := filepath.Abs(ConfigFile())
+	if err != nil {
+		return err
+	}
+
+	// Watch the directory: editors and Kubernetes replace the file rather
+	// than writing to it. Without a file watcher SIGHUP still works.
+	var (
+		events <-chan fsnotify.Event
+		errs   <-chan error
+	)
+	fsw, err := fsnotify.NewWatcher()
+	if err == nil {
+		defer fsw.Close()
+		err = fsw.Add(filepath.Dir(file))
+	}
+	if err != nil {
+		w.logger.Error("failed to watch config file, reload with SIGHUP instead", err)
+	} else {
+		events, errs = fsw.Events, fsw.Errors
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	w.logger.With("file", file).Info("watching configuration")
+
+	debounce := time.NewTimer(0)
+	<-debounce.C
+	for {
+		select {
+		case <-ctx.Done():
+			debounce.Stop()
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(event.Name) == file && !event.Has(fsnotify.Chmod) {
+				debounce.Reset(reloadDebounce)
+			}
+		case err, ok := <-errs:
+			if !ok {
+				return nil
+			}
+			w.logger.Error("config watcher error", err)
+		case <-debounce.C:
+			w.Reload("file changed")
+		case <-hup:
+			w.Reload("SIGHUP")
+		}
+	}
+}
+
+// Reload reads the configuration again and applies it if it is valid and
+// only reloadable settings changed. Otherwise the running configuration is
+// kept.
+func (w *Watcher) Reload(reason string) {
+	log := w.logger.With("reason", reason)
+
+	cfg, err := Load()
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		log.Error("configuration reload rejected", err)
+		return
+	}
+
+	next := snapshot()
+	changed := diff(w.current, next)
+	if len(changed) == 0 {
+		log.Debug("configuration unchanged")
+		return
+	}
+
+	var fixed []string
+	for _, key := range changed {
+		if !isReloadable(key) {
+			fixed = append(fixed, key)
+		}
+	}
+	if len(fixed) > 0 {
+		log.With("changed", changed).
+			With("not_reloadable", fixed).
+			Error("configuration reload rejected", fmt.Errorf("settings require a restart: %s", strings.Join(fixed, ", ")))
+		return
+	}
+
+	w.current = next
+	w.apply(cfg, changed)
+	log.With("changed", changed).Info("configuration reloaded")
+}
+
+// snapshot captures the raw value of every setting currently loaded.
+func snapshot() map[string]string {
+	values := make(map[string]string)
+	for _, key := range viperKeys() {
+		values[key] = formatValue(viper.Get(key))
+	}
+	return values
+}
+
+func diff(old, next map[string]string) []string {
+	var changed []string
+	for key, value := range next {
+		if prev, ok := old[key]; !ok || prev != value {
+			changed = append(changed, key)
+		}
+	}
+	for key := range old {
+		if _, ok := next[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func isReloadable(key string) bool {
+	return slices.ContainsFunc(reloadable, func(r string) bool {
+		return key == r || strings.HasSuffix(r, ".") && strings.HasPrefix(key, r)
+	})
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+	"github.com/krisadabig/supreme-ms-item/internal/core/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AdminHandler struct {
+	logLevelService *services.LogLevelService
+	logger          ports.Logger
+}
+
+func NewAdminHandler(logLevelService *services.LogLevelService, log ports.Logger) *AdminHandler {
+	return &AdminHandler{
+		logLevelService: logLevelService,
+		logger:          log,
+	}
+}
+
+// logLevelRequest is the body of PUT /admin/log-level. Duration is a Go
+// duration such as "30m"; it defaults to domain.DefaultLogLevelDuration.
+type logLevelRequest struct {
+	Level    string `json:"level"`
+	Duration string `json:"duration"`
+}
+
+func (h *AdminHandler) GetLogLevel(c echo.Context) error {
+	status, err := h.logLevelService.Status(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, status)
+}
+
+// SetLogLevel changes the log level temporarily.
+func (h *AdminHandler) SetLogLevel(c echo.Context) error {
+	log := h.logger.WithContext(c.Request().Context())
+
+	var req logLevelRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		log.With("error", err.Error()).Warn("invalid request payload")
+		return fmt.Errorf("%w: body must be a JSON object with level and duration", domain.ErrInvalidLogLevel)
+	}
+
+	var duration time.Duration
+	if req.Duration != "" {
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil {
+			return fmt.Errorf("%w: invalid duration %q", domain.ErrInvalidLogLevel, req.Duration)
+		}
+		duration = d
+	}
+
+	status, err := h.logLevelService.Override(c.Request().Context(), req.Level, duration)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, status)
+}
+
+func (h *AdminHandler) RegisterRoutes(e *echo.Group) {
+	adminGroup := e.Group("/admin")
+	adminGroup.GET("/log-level", h.GetLogLevel)
+	adminGroup.PUT("/log-level", h.SetLogLevel)
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+	"github.com/krisadabig/supreme-ms-item/internal/utils/contextutils"
+
+	"github.com/labstack/echo/v4"
+)
+
+const bearerScheme = "Bearer"
+
+// Auth returns a middleware that authenticates requests with an
+// "Authorization: Bearer <token>" header. The verified user is stored in the
+// request context; handlers must read the caller only from there.
+func Auth(verifier ports.TokenVerifier, log ports.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			reqLog := log.WithContext(req.Context())
+
+			token, ok := bearerToken(req.Header.Get(echo.HeaderAuthorization))
+			if !ok {
+				reqLog.Warn("missing bearer token")
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, bearerScheme)
+				return domain.ErrUnauthenticated
+			}
+
+			user, err := verifier.Verify(req.Context(), token)
+			if err != nil {
+				reqLog.With("error", err.Error()).Warn("invalid bearer token")
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, bearerScheme+` error="invalid_token"`)
+				return err
+			}
+
+			ctx := contextutils.ContextWithUser(req.Context(), user)
+			c.SetRequest(req.WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
+
+// bearerToken extracts the token from an Authorization header value.
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, bearerScheme) {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/logger"
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+	"github.com/krisadabig/supreme-ms-item/internal/utils/contextutils"
+)
+
+// tokenVerifier accepts the token "valid" as alice.
+type tokenVerifier struct{}
+
+func (tokenVerifier) Verify(_ context.Context, token string) (*domain.User, error) {
+	if token != "valid" {
+		return nil, domain.ErrInvalidToken
+	}
+	return &domain.User{ID: "alice"}, nil
+}
+
+func TestAuth(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		wantErr       error
+		wantChallenge string
+	}{
+		{"valid token", "Bearer valid", nil, ""},
+		{"scheme is case-insensitive", "bearer valid", nil, ""},
+		{"missing header", "", domain.ErrUnauthenticated, "Bearer"},
+		{"other scheme", "Basic dmFsaWQ=", domain.ErrUnauthenticated, "Bearer"},
+		{"empty token", "Bearer ", domain.ErrUnauthenticated, "Bearer"},
+		{"invalid token", "Bearer forged", domain.ErrInvalidToken, `Bearer error="invalid_token"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/items", nil)
+			req.Header.Set("X-User-ID", "mallory")
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+
+			var user *domain.User
+			err := Auth(tokenVerifier{}, logger.New(logger.WithOutput(io.Discard)))(func(c echo.Context) error {
+				user, _ = contextutils.UserFromContext(c.Request().Context())
+				return nil
+			})(c)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Auth returned %v, want %v", err, tt.wantErr)
+			}
+			if got := rec.Header().Get(echo.HeaderWWWAuthenticate); got != tt.wantChallenge {
+				t.Errorf("WWW-Authenticate %q, want %q", got, tt.wantChallenge)
+			}
+			if tt.wantErr == nil && (user == nil || user.ID != "alice") {
+				t.Errorf("handler saw user %+v, want alice", user)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package http
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/labstack/echo/v4"
+)
+
+// IPExtractor returns how the client IP of a request is found. Without
+// trustedProxies it is the address of the peer, as X-Forwarded-For and
+// X-Real-IP can be set by any client. Otherwise, when the peer is one of the
+// trustedProxies, it is the last address of X-Forwarded-For that is not.
+// trustedProxies are CIDRs, e.g. 10.0.0.0/8.
+func IPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range trustedProxies {
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %w", proxy, err)
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestIPExtractor(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		forwardedFor   string
+		want           string
+	}{
+		{"headers are ignored without trusted proxies", nil, "10.0.0.1:1234", "203.0.113.7", "10.0.0.1"},
+		{"trusted proxies forward the client", []string{"10.0.0.0/8"}, "10.0.0.1:1234", "203.0.113.7, 10.0.0.2", "203.0.113.7"},
+		{"spoofed entries before the client are ignored", []string{"10.0.0.0/8"}, "10.0.0.1:1234", "198.51.100.1, 203.0.113.7", "203.0.113.7"},
+		{"other peers cannot forward", []string{"10.0.0.0/8"}, "192.168.0.1:1234", "203.0.113.7", "192.168.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extract, err := IPExtractor(tt.trustedProxies)
+			if err != nil {
+				t.Fatalf("IPExtractor: %v", err)
+			}
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set(echo.HeaderXForwardedFor, tt.forwardedFor)
+			req.Header.Set(echo.HeaderXRealIP, "198.51.100.9")
+			if got := extract(req); got != tt.want {
+				t.Errorf("client IP %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := IPExtractor([]string{"10.0.0.1"}); err == nil {
+		t.Error("IPExtractor accepted an address without a prefix length")
+	}
+}
@@ -2,21 +2,56 @@ package http
 
 import (
 	"net/http"
+	"slices"
+	"sync/atomic"
 
 	"github.com/krisadabig/supreme-ms-item/internal/constants"
 	"github.com/labstack/echo/v4"
 	echoMiddleware "github.com/labstack/echo/v4/middleware"
 )
 
-// CORSMiddleware returns a configured CORS middleware instance.
-// When allowedOrigins is empty, it falls back to a wildcard.
-func CORSMiddleware(allowedOrigins []string) echo.MiddlewareFunc {
-	if len(allowedOrigins) == 0 {
-		allowedOrigins = []string{"*"}
+// AllowedOrigins is the CORS origin allowlist. It can be replaced while the
+// server runs.
+type AllowedOrigins struct {
+	cors atomic.Pointer[echo.MiddlewareFunc]
+}
+
+// NewAllowedOrigins creates an allowlist of origins.
+func NewAllowedOrigins(origins []string) *AllowedOrigins {
+	a := &AllowedOrigins{}
+	a.Set(origins)
+	return a
+}
+
+// Set replaces the allowlist. Origins may hold wildcards, e.g.
+// https://*.example.com, and are allowed with credentials. A "*" entry, or
+// an empty list, allows every origin but without credentials, since
+// browsers must not send cookies or Authorization to any site that asks.
+func (a *AllowedOrigins) Set(origins []string) {
+	config := corsConfig()
+	if len(origins) == 0 || slices.Contains(origins, "*") {
+		config.AllowOrigins = []string{"*"}
+	} else {
+		config.AllowOrigins = slices.Clone(origins)
+		config.AllowCredentials = true
 	}
+	cors := echoMiddleware.CORSWithConfig(config)
+	a.cors.Store(&cors)
+}
 
-	config := echoMiddleware.CORSConfig{
-		AllowOrigins: allowedOrigins,
+// CORSMiddleware returns a CORS middleware checking origins against the
+// current allowedOrigins on every request.
+func CORSMiddleware(allowedOrigins *AllowedOrigins) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			return (*allowedOrigins.cors.Load())(next)(c)
+		}
+	}
+}
+
+// corsConfig returns the CORS settings shared by every allowlist.
+func corsConfig() echoMiddleware.CORSConfig {
+	return echoMiddleware.CORSConfig{
 		AllowMethods: []string{
 			http.MethodGet,
 			http.MethodPost,
@@ -30,14 +65,16 @@ func CORSMiddleware(allowedOrigins []string) echo.MiddlewareFunc {
 			echo.HeaderContentType,
 			echo.HeaderAuthorization,
 			"X-Requested-With",
-			"X-Correlation-Id",
-			constants.HeaderUserID,
+			constants.HeaderCorrelationID,
+			headerIfMatch,
+			headerIfNoneMatch,
+			headerTraceparent,
+			headerTracestate,
 		},
 		ExposeHeaders: []string{
 			echo.HeaderAuthorization,
+			headerETag,
+			headerTraceparent,
 		},
-		AllowCredentials: true,
 	}
-
-	return echoMiddleware.CORSWithConfig(config)
 }
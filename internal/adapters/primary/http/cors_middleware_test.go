@@ -0,0 +1,66 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestCORS(t *testing.T) {
+	tests := []struct {
+		name            string
+		origins         []string
+		origin          string
+		wantOrigin      string
+		wantCredentials string
+	}{
+		{"listed origin", []string{"https://app.example"}, "https://app.example", "https://app.example", "true"},
+		{"unlisted origin", []string{"https://app.example"}, "https://evil.example", "", ""},
+		{"subdomain wildcard", []string{"https://*.example.com"}, "https://app.example.com", "https://app.example.com", "true"},
+		{"outside the subdomain wildcard", []string{"https://*.example.com"}, "https://example.org", "", ""},
+		{"wildcard", []string{"*"}, "https://evil.example", "*", ""},
+		{"wildcard among origins", []string{"https://app.example", "*"}, "https://app.example", "*", ""},
+		{"empty list", nil, "https://evil.example", "*", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(CORSMiddleware(NewAllowedOrigins(tt.origins)))
+			e.GET("/items", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/items", nil)
+			req.Header.Set(echo.HeaderOrigin, tt.origin)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get(echo.HeaderAccessControlAllowOrigin); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin %q, want %q", got, tt.wantOrigin)
+			}
+			if got := rec.Header().Get(echo.HeaderAccessControlAllowCredentials); got != tt.wantCredentials {
+				t.Errorf("Access-Control-Allow-Credentials %q, want %q", got, tt.wantCredentials)
+			}
+		})
+	}
+}
+
+func TestCORSReload(t *testing.T) {
+	origins := NewAllowedOrigins([]string{"https://old.example"})
+	e := echo.New()
+	e.Use(CORSMiddleware(origins))
+	e.GET("/items", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	allowed := func(origin string) bool {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		req.Header.Set(echo.HeaderOrigin, origin)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Header().Get(echo.HeaderAccessControlAllowOrigin) == origin
+	}
+
+	origins.Set([]string{"https://new.example"})
+	if allowed("https://old.example") || !allowed("https://new.example") {
+		t.Error("Set did not replace the allowlist of a running middleware")
+	}
+}
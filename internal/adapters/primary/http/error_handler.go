@@ -0,0 +1,151 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/krisadabig/supreme-ms-item/internal/constants"
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+	"github.com/krisadabig/supreme-ms-item/internal/utils/contextutils"
+
+	"github.com/labstack/echo/v4"
+)
+
+// MIMEApplicationProblemJSON is the media type of RFC 7807 error bodies.
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body.
+type Problem struct {
+	Type          string `json:"type"`
+	Title         string `json:"title"`
+	Status        int    `json:"status"`
+	Detail        string `json:"detail,omitempty"`
+	Instance      string `json:"instance,omitempty"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+	// Errors lists the invalid fields of a rejected payload.
+	Errors []domain.FieldError `json:"errors,omitempty"`
+}
+
+// problemType describes how a domain error is reported to clients.
+type problemType struct {
+	err    error
+	status int
+	slug   string
+	title  string
+	// exposeDetail is set for errors whose message is written for clients.
+	exposeDetail bool
+}
+
+// problemTypes is checked in order; the first entry matching the error wins.
+var problemTypes = []problemType{
+	{domain.ErrInvalidQuery, http.StatusBadRequest, "invalid-query", "Invalid query", true},
+	{domain.ErrInvalidCursor, http.StatusBadRequest, "invalid-cursor", "Invalid cursor", false},
+	{domain.ErrInvalidToken, http.StatusUnauthorized, "invalid-token", "Invalid bearer token", false},
+	{domain.ErrUnauthenticated, http.StatusUnauthorized, "unauthenticated", "Authentication required", false},
+	{domain.ErrForbidden, http.StatusForbidden, "forbidden", "Access to this resource is forbidden", false},
+	{domain.ErrItemNotFound, http.StatusNotFound, "item-not-found", "Item not found", false},
+	// Items of other users are reported as missing so their IDs reveal nothing.
+	{domain.ErrItemNotOwned, http.StatusNotFound, "item-not-found", "Item not found", false},
+	{domain.ErrItemExists, http.StatusConflict, "item-exists", "Item already exists", false},
+	{domain.ErrInvalidPatch, http.StatusBadRequest, "invalid-patch", "Invalid patch document", true},
+	{domain.ErrPatchConflict, http.StatusConflict, "patch-conflict", "Patch cannot be applied", true},
+	{domain.ErrInvalidLogLevel, http.StatusBadRequest, "invalid-log-level", "Invalid log level", true},
+	{domain.ErrVersionMismatch, http.StatusPreconditionFailed, "version-mismatch", "Item was modified by another request", false},
+	{domain.ErrInvalidItem, http.StatusUnprocessableEntity, "invalid-item", "Invalid item", false},
+	{domain.ErrInvalidReference, http.StatusUnprocessableEntity, "invalid-reference", "Referenced resource does not exist", false},
+	{domain.ErrInvalidBatch, http.StatusBadRequest, "invalid-batch", "Invalid batch", true},
+	{domain.ErrBatchTooLarge, http.StatusRequestEntityTooLarge, "batch-too-large", "Batch too large", true},
+	{domain.ErrBatchAborted, http.StatusFailedDependency, "batch-aborted", "Not applied because another item of the batch failed", false},
+	{errRateLimited, http.StatusTooManyRequests, "rate-limited", "Too many requests", false},
+	{domain.ErrUnavailable, http.StatusServiceUnavailable, "unavailable", "Service temporarily unavailable", false},
+	{domain.ErrTimeout, http.StatusGatewayTimeout, "timeout", "The operation timed out", false},
+}
+
+// problemTypeURI builds the identifier of a problem type.
+func problemTypeURI(slug string) string {
+	return "urn:supreme-ms-item:problem:" + slug
+}
+
+// ErrorHandler returns an Echo HTTPErrorHandler that maps domain errors to
+// HTTP statuses and renders them as application/problem+json.
+func ErrorHandler(log ports.Logger) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		problem := NewProblem(err)
+		problem.Instance = c.Request().URL.Path
+		problem.CorrelationID = correlationID(c)
+
+		if problem.Status == http.StatusServiceUnavailable || problem.Status == http.StatusTooManyRequests {
+			c.Response().Header().Set("Retry-After", "1")
+		}
+
+		var writeErr error
+		if c.Request().Method == http.MethodHead {
+			writeErr = c.NoContent(problem.Status)
+		} else {
+			writeErr = writeProblem(c, problem)
+		}
+		if writeErr != nil {
+			log.WithContext(c.Request().Context()).Error("failed to write error response", writeErr)
+		}
+	}
+}
+
+// correlationID returns the correlation ID of the request c answers.
+func correlationID(c echo.Context) string {
+	if id := contextutils.RequestIDFromContext(c.Request().Context()); id != "" {
+		return id
+	}
+	return c.Response().Header().Get(constants.HeaderCorrelationID)
+}
+
+// NewProblem builds the problem details describing err.
+func NewProblem(err error) Problem {
+	for _, pt := range problemTypes {
+		if errors.Is(err, pt.err) {
+			p := Problem{
+				Type:   problemTypeURI(pt.slug),
+				Title:  pt.title,
+				Status: pt.status,
+			}
+			if pt.exposeDetail {
+				p.Detail = err.Error()
+			}
+			var verr *domain.ValidationError
+			if errors.As(err, &verr) {
+				p.Detail = "The request payload contains invalid fields"
+				p.Errors = verr.Errors
+			}
+			return p
+		}
+	}
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		p := Problem{
+			Type:   "about:blank",
+			Title:  http.StatusText(he.Code),
+			Status: he.Code,
+		}
+		if msg, ok := he.Message.(string); ok && msg != p.Title {
+			p.Detail = msg
+		}
+		return p
+	}
+
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusInternalServerError),
+		Status: http.StatusInternalServerError,
+	}
+}
+
+func writeProblem(c echo.Context, problem Problem) error {
+	// c.JSON keeps an explicitly set content type.
+	c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+	return c.JSON(problem.Status, problem)
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/logger"
+	"github.com/krisadabig/supreme-ms-item/internal/constants"
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+)
+
+func TestNewProblem(t *testing.T) {
+	var verr domain.ValidationError
+	verr.Add(domain.ItemFieldTitle, domain.CodeRequired, "title is required")
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantType   string
+		wantDetail string
+	}{
+		{"wrapped not found", fmt.Errorf("fetch: %w", domain.ErrItemNotFound), http.StatusNotFound, "urn:supreme-ms-item:problem:item-not-found", ""},
+		{"item of another user", domain.ErrItemNotOwned, http.StatusNotFound, "urn:supreme-ms-item:problem:item-not-found", ""},
+		{"forbidden", domain.ErrForbidden, http.StatusForbidden, "urn:supreme-ms-item:problem:forbidden", ""},
+		{"version mismatch", domain.ErrVersionMismatch, http.StatusPreconditionFailed, "urn:supreme-ms-item:problem:version-mismatch", ""},
+		{"detail of a query error", fmt.Errorf("%w: sort must be title", domain.ErrInvalidQuery), http.StatusBadRequest, "urn:supreme-ms-item:problem:invalid-query", "invalid query: sort must be title"},
+		{"hidden detail", fmt.Errorf("%w: pq: connection refused", domain.ErrUnavailable), http.StatusServiceUnavailable, "urn:supreme-ms-item:problem:unavailable", ""},
+		{"validation error", &verr, http.StatusUnprocessableEntity, "urn:supreme-ms-item:problem:invalid-item", "The request payload contains invalid fields"},
+		{"echo error", echo.NewHTTPError(http.StatusBadRequest, "Invalid item ID"), http.StatusBadRequest, "about:blank", "Invalid item ID"},
+		{"unknown error", errors.New("nil pointer dereference"), http.StatusInternalServerError, "about:blank", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProblem(tt.err)
+			if p.Status != tt.wantStatus || p.Type != tt.wantType || p.Detail != tt.wantDetail {
+				t.Errorf("NewProblem = %d %s %q, want %d %s %q", p.Status, p.Type, p.Detail, tt.wantStatus, tt.wantType, tt.wantDetail)
+			}
+		})
+	}
+
+	if p := NewProblem(&verr); len(p.Errors) != 1 || p.Errors[0].Field != domain.ItemFieldTitle {
+		t.Errorf("NewProblem of a validation error lists %+v", p.Errors)
+	}
+}
+
+func TestErrorHandler(t *testing.T) {
+	handle := ErrorHandler(logger.New(logger.WithOutput(io.Discard)))
+
+	serve := func(method string, err error) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/items/7", nil)
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		c.Response().Header().Set(constants.HeaderCorrelationID, "req-1")
+		handle(err, c)
+		return rec
+	}
+
+	rec := serve(http.MethodGet, domain.ErrUnavailable)
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") != "1" {
+		t.Errorf("unavailable: %d with Retry-After %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if ct := rec.Header().Get(echo.HeaderContentType); ct != MIMEApplicationProblemJSON {
+		t.Errorf("Content-Type %q, want %q", ct, MIMEApplicationProblemJSON)
+	}
+	var p Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if p.Instance != "/api/v1/items/7" || p.CorrelationID != "req-1" {
+		t.Errorf("problem has instance %q and correlation ID %q", p.Instance, p.CorrelationID)
+	}
+
+	if rec := serve(http.MethodHead, domain.ErrItemNotFound); rec.Code != http.StatusNotFound || rec.Body.Len() != 0 {
+		t.Errorf("HEAD: %d with a %d byte body, want 404 without a body", rec.Code, rec.Body.Len())
+	}
+}
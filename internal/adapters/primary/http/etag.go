@@ -0,0 +1,88 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Conditional request headers, see RFC 9110 section 13.
+const (
+	headerETag        = "ETag"
+	headerIfMatch     = "If-Match"
+	headerIfNoneMatch = "If-None-Match"
+)
+
+// itemETag returns the strong entity tag of an item, derived from its version.
+func itemETag(item *domain.Item) string {
+	return `"` + strconv.FormatInt(item.Version, 10) + `"`
+}
+
+// pageETag returns a weak entity tag identifying the items, versions and
+// continuation of a page.
+func pageETag(page *domain.ItemPage) string {
+	h := sha256.New()
+	var buf [16]byte
+	for _, item := range page.Items {
+		binary.BigEndian.PutUint64(buf[:8], uint64(item.ID))
+		binary.BigEndian.PutUint64(buf[8:], uint64(item.Version))
+		h.Write(buf[:])
+	}
+	h.Write([]byte(page.NextCursor))
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// parseIfMatch returns the version required by the If-Match header, or 0 when
+// the header is absent or "*". A tag that cannot name a current version, such
+// as a weak tag or a list, yields domain.ErrVersionMismatch.
+func parseIfMatch(c echo.Context) (int64, error) {
+	header := strings.TrimSpace(c.Request().Header.Get(headerIfMatch))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	if len(header) < 3 || header[0] != '"' || header[len(header)-1] != '"' {
+		return 0, domain.ErrVersionMismatch
+	}
+	version, err := strconv.ParseInt(header[1:len(header)-1], 10, 64)
+	if err != nil || version <= 0 {
+		return 0, domain.ErrVersionMismatch
+	}
+	return version, nil
+}
+
+// notModified reports whether the If-None-Match header matches etag using
+// the weak comparison required for GET requests.
+func notModified(c echo.Context, etag string) bool {
+	header := c.Request().Header.Get(headerIfNoneMatch)
+	if header == "" {
+		return false
+	}
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonWithETag writes v with the given entity tag, or 304 Not Modified when
+// the client already holds that representation.
+func jsonWithETag(c echo.Context, code int, etag string, v any) error {
+	c.Response().Header().Set(headerETag, etag)
+	if code == http.StatusOK && notModified(c, etag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.JSON(code, v)
+}
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+	"github.com/krisadabig/supreme-ms-item/internal/core/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type HealthHandler struct {
+	healthService *services.HealthService
+}
+
+func NewHealthHandler(healthService *services.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// Liveness answers whether the process is alive.
+func (h *HealthHandler) Liveness(c echo.Context) error {
+	return writeHealthReport(c, h.healthService.Liveness(c.Request().Context()))
+}
+
+// Readiness answers whether the service can take traffic, with the status
+// and latency of every dependency.
+func (h *HealthHandler) Readiness(c echo.Context) error {
+	return writeHealthReport(c, h.healthService.Readiness(c.Request().Context()))
+}
+
+func (h *HealthHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/healthz", h.Liveness)
+	e.GET("/readyz", h.Readiness)
+}
+
+func writeHealthReport(c echo.Context, report domain.HealthReport) error {
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	status := http.StatusOK
+	if report.Status != domain.HealthStatusUp {
+		status = http.StatusServiceUnavailable
+	}
+	return c.JSON(status, report)
+}
@@ -0,0 +1,231 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxBatchItemBytes bounds the JSON of one item of a batch. An item whose
+// title and description are at their maximum lengths, every character
+// escaped as \uXXXX, still fits.
+const maxBatchItemBytes = 32 << 10
+
+// batchRequest is the body of the batch endpoints. Items are decoded one by
+// one so a malformed item is reported at its index.
+type batchRequest struct {
+	Mode  domain.BatchMode  `json:"mode"`
+	Items []json.RawMessage `json:"items"`
+}
+
+// batchItemResult is the outcome of one item of a batch.
+type batchItemResult struct {
+	Index  int          `json:"index"`
+	Status int          `json:"status"`
+	Item   *domain.Item `json:"item,omitempty"`
+	Error  *Problem     `json:"error,omitempty"`
+}
+
+type batchResponse struct {
+	Results []batchItemResult `json:"results"`
+}
+
+// CreateItems creates a batch of items.
+func (h *ItemHandler) CreateItems(c echo.Context) error {
+	log := h.logger.WithContext(c.Request().Context())
+
+	req, err := h.bindBatch(c)
+	if err != nil {
+		log.With("error", err.Error()).Warn("invalid batch payload")
+		return err
+	}
+
+	items := make([]*domain.Item, len(req.Items))
+	decodeErrs := make([]error, len(req.Items))
+	for i, raw := range req.Items {
+		items[i] = &domain.Item{}
+		decodeErrs[i] = decodeItem(raw, items[i], "Item")
+	}
+
+	results, err := runBatch(req.Mode, decodeErrs, func(ok []int) ([]domain.BatchResult, error) {
+		return h.itemService.CreateBatch(c.Request().Context(), pick(items, ok), req.Mode)
+	})
+	if err != nil {
+		log.Error("failed to create items", err)
+		return err
+	}
+	return writeBatch(c, results, http.StatusCreated)
+}
+
+// ReplaceItems replaces the title and description of a batch of items,
+// each identified by its id and optionally required to be at its version.
+func (h *ItemHandler) ReplaceItems(c echo.Context) error {
+	log := h.logger.WithContext(c.Request().Context())
+
+	req, err := h.bindBatch(c)
+	if err != nil {
+		log.With("error", err.Error()).Warn("invalid batch payload")
+		return err
+	}
+
+	items := make([]*domain.Item, len(req.Items))
+	decodeErrs := make([]error, len(req.Items))
+	for i, raw := range req.Items {
+		items[i] = &domain.Item{}
+		decodeErrs[i] = decodeItem(raw, items[i], "Item")
+		if decodeErrs[i] == nil && items[i].ID <= 0 {
+			decodeErrs[i] = echo.NewHTTPError(http.StatusBadRequest, "Item id must be a positive integer")
+		}
+	}
+
+	results, err := runBatch(req.Mode, decodeErrs, func(ok []int) ([]domain.BatchResult, error) {
+		return h.itemService.ReplaceBatch(c.Request().Context(), pick(items, ok), req.Mode)
+	})
+	if err != nil {
+		log.Error("failed to replace items", err)
+		return err
+	}
+	return writeBatch(c, results, http.StatusOK)
+}
+
+// DeleteItems moves a batch of items to the trash, each identified by its
+// id and optionally required to be at its version.
+func (h *ItemHandler) DeleteItems(c echo.Context) error {
+	log := h.logger.WithContext(c.Request().Context())
+
+	req, err := h.bindBatch(c)
+	if err != nil {
+		log.With("error", err.Error()).Warn("invalid batch payload")
+		return err
+	}
+
+	refs := make([]domain.ItemRef, len(req.Items))
+	decodeErrs := make([]error, len(req.Items))
+	for i, raw := range req.Items {
+		decodeErrs[i] = decodeRef(raw, &refs[i])
+	}
+
+	results, err := runBatch(req.Mode, decodeErrs, func(ok []int) ([]domain.BatchResult, error) {
+		return h.itemService.DeleteBatch(c.Request().Context(), pick(refs, ok), req.Mode)
+	})
+	if err != nil {
+		log.Error("failed to delete items", err)
+		return err
+	}
+	return writeBatch(c, results, http.StatusNoContent)
+}
+
+// bindBatch decodes a batch request, leaving its items raw, and checks its
+// mode and size. The body is bounded by the size of the largest batch
+// allowed, so an oversized one is rejected while it is read rather than
+// once held in memory. The mode defaults to atomic.
+func (h *ItemHandler) bindBatch(c echo.Context) (*batchRequest, error) {
+	limit := int64(h.itemService.MaxBatchSize())*maxBatchItemBytes + maxBatchItemBytes
+	req := c.Request()
+	req.Body = http.MaxBytesReader(c.Response(), req.Body, limit)
+	dec := json.NewDecoder(req.Body)
+	dec.DisallowUnknownFields()
+
+	var batch batchRequest
+	if err := dec.Decode(&batch); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, fmt.Errorf("%w: request body exceeds %d bytes", domain.ErrBatchTooLarge, limit)
+		}
+		return nil, echo.NewHTTPError(http.StatusBadRequest,
+			"Request body must be a JSON object with an items array and an optional mode")
+	}
+	if batch.Mode == "" {
+		batch.Mode = domain.BatchAtomic
+	}
+	if err := h.itemService.CheckBatch(len(batch.Items), batch.Mode); err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// decodeRef decodes the reference to an item of a batch delete.
+func decodeRef(raw json.RawMessage, ref *domain.ItemRef) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(ref); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Item must be a JSON object with an id and an optional version")
+	}
+	if ref.ID <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Item id must be a positive integer")
+	}
+	return nil
+}
+
+// runBatch passes the indexes of the items that decoded to write and merges
+// its results with the decoding failures. An atomic batch with an item that
+// failed to decode is not written at all.
+func runBatch(mode domain.BatchMode, decodeErrs []error, write func(ok []int) ([]domain.BatchResult, error)) ([]domain.BatchResult, error) {
+	results := make([]domain.BatchResult, len(decodeErrs))
+	var ok []int
+	for i, err := range decodeErrs {
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		ok = append(ok, i)
+	}
+
+	if len(ok) < len(decodeErrs) && (mode == domain.BatchAtomic || len(ok) == 0) {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i].Err = domain.ErrBatchAborted
+			}
+		}
+		return results, nil
+	}
+
+	written, err := write(ok)
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range ok {
+		results[i] = written[j]
+	}
+	return results, nil
+}
+
+// pick returns the elements of s at indexes.
+func pick[T any](s []T, indexes []int) []T {
+	picked := make([]T, len(indexes))
+	for j, i := range indexes {
+		picked[j] = s[i]
+	}
+	return picked
+}
+
+// writeBatch renders the result of every item. The response status is
+// 200 OK, or 201 Created for a batch create, when every item succeeded and
+// 207 Multi-Status otherwise; each item carries its own status, success
+// being reported as itemStatus.
+func writeBatch(c echo.Context, results []domain.BatchResult, itemStatus int) error {
+	status := http.StatusOK
+	if itemStatus == http.StatusCreated {
+		status = http.StatusCreated
+	}
+
+	resp := batchResponse{Results: make([]batchItemResult, len(results))}
+	for i, r := range results {
+		out := batchItemResult{Index: i, Status: itemStatus, Item: r.Item}
+		if r.Err != nil {
+			problem := NewProblem(r.Err)
+			problem.Instance = fmt.Sprintf("%s#/items/%d", c.Request().URL.Path, i)
+			problem.CorrelationID = correlationID(c)
+			out = batchItemResult{Index: i, Status: problem.Status, Error: &problem}
+			status = http.StatusMultiStatus
+		}
+		resp.Results[i] = out
+	}
+	return c.JSON(status, resp)
+}
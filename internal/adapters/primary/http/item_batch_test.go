@@ -0,0 +1,81 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/logger"
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/storage/memory"
+	"github.com/krisadabig/supreme-ms-item/internal/constants"
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+	"github.com/krisadabig/supreme-ms-item/internal/core/services"
+	"github.com/krisadabig/supreme-ms-item/internal/utils/contextutils"
+)
+
+// postBatch sends body to CreateItems, as alice, with a service allowing
+// batches of two items.
+func postBatch(t *testing.T, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	log := logger.New(logger.WithOutput(io.Discard))
+	h := NewItemHandler(services.NewItemService(memory.NewMemoryItemRepository(), log, services.WithMaxBatchSize(2)), log)
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler(log)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/items:batch", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	ctx := contextutils.ContextWithUser(req.Context(), &domain.User{ID: "alice"})
+	req = req.WithContext(contextutils.ContextWithRequestID(ctx, "req-1"))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Response().Header().Set(constants.HeaderCorrelationID, "req-1")
+	if err := h.CreateItems(c); err != nil {
+		e.HTTPErrorHandler(err, c)
+	}
+	return rec
+}
+
+func TestCreateItemsReportsItemProblems(t *testing.T) {
+	rec := postBatch(t, `{"mode": "partial", "items": [{"title": "first"}, {"title": ""}]}`)
+	if rec.Code != http.StatusMultiStatus {
+		t.Fatalf("status %d, want %d: %s", rec.Code, http.StatusMultiStatus, rec.Body)
+	}
+
+	var resp batchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Results) != 2 || resp.Results[0].Status != http.StatusCreated || resp.Results[1].Error == nil {
+		t.Fatalf("results %+v, want the first item created and the second failed", resp.Results)
+	}
+	problem := resp.Results[1].Error
+	if problem.CorrelationID != "req-1" || problem.Instance != "/api/v1/items:batch#/items/1" {
+		t.Errorf("item problem has correlation id %q and instance %q", problem.CorrelationID, problem.Instance)
+	}
+}
+
+func TestCreateItemsLimitsBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"within the limit", `{"items": [{"title": "first"}, {"title": "second"}]}`, http.StatusCreated},
+		{"too many items", `{"items": [{"title": "a"}, {"title": "b"}, {"title": "c"}]}`, http.StatusRequestEntityTooLarge},
+		// Three times the bound of an item exceeds the body allowed for two.
+		{"too large", `{"items": [{"title": "` + strings.Repeat("a", 3*maxBatchItemBytes) + `"}]}`, http.StatusRequestEntityTooLarge},
+		{"malformed", `{"items": [`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := postBatch(t, tt.body); rec.Code != tt.want {
+				t.Errorf("status %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
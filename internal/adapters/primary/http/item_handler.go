@@ -2,13 +2,16 @@ package http
 
 import (
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"strconv"
+	"time"
 
-	"github.com/krisadabig/supreme-ms-item/internal/constants"
 	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
 	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
 	"github.com/krisadabig/supreme-ms-item/internal/core/services"
+	"github.com/krisadabig/supreme-ms-item/internal/utils/contextutils"
 
 	"github.com/labstack/echo/v4"
 )
@@ -29,40 +32,83 @@ func (h *ItemHandler) CreateItem(c echo.Context) error {
 	log := h.logger.WithContext(c.Request().Context())
 
 	var item domain.Item
-	if err := c.Bind(&item); err != nil {
+	if err := bindItem(c, &item); err != nil {
 		log.With("error", err.Error()).Warn("invalid request payload")
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request payload")
+		return err
 	}
 
-	userID := c.Request().Header.Get(constants.HeaderUserID)
-	if userID == "" {
-		log.Warn("user id is required")
-		return echo.NewHTTPError(http.StatusBadRequest, "userID is required")
-	}
-	item.UserID = userID
-
 	if err := h.itemService.Create(c.Request().Context(), &item); err != nil {
 		log.Error("failed to create item", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create item")
+		return err
 	}
 
-	return c.JSON(http.StatusCreated, item)
+	return jsonWithETag(c, http.StatusCreated, itemETag(&item), item)
 }
 
-func (h *ItemHandler) UpdateItem(c echo.Context) error {
+func (h *ItemHandler) ReplaceItem(c echo.Context) error {
 	log := h.logger.WithContext(c.Request().Context())
 
+	id, err := getIDParam(c)
+	if err != nil {
+		log.With("error", err.Error()).Warn("invalid item id")
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid item ID")
+	}
+
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		log.Warn("unusable If-Match header")
+		return err
+	}
+
 	var item domain.Item
-	if err := c.Bind(&item); err != nil {
+	if err := bindItem(c, &item); err != nil {
 		log.With("error", err.Error()).Warn("invalid update payload")
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request payload")
+		return err
+	}
+
+	if err := h.itemService.Replace(c.Request().Context(), id, expectedVersion, &item); err != nil {
+		log.Error("failed to replace item", err)
+		return err
+	}
+
+	c.Response().Header().Set(headerETag, itemETag(&item))
+	return c.JSON(http.StatusOK, item)
+}
+
+func (h *ItemHandler) PatchItem(c echo.Context) error {
+	log := h.logger.WithContext(c.Request().Context())
+
+	id, err := getIDParam(c)
+	if err != nil {
+		log.With("error", err.Error()).Warn("invalid item id")
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid item ID")
+	}
+
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		log.Warn("unusable If-Match header")
+		return err
+	}
+
+	patchType, err := getPatchType(c)
+	if err != nil {
+		log.With("error", err.Error()).Warn("unsupported patch media type")
+		return err
+	}
+
+	doc, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		log.With("error", err.Error()).Warn("failed to read patch document")
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to read request body")
 	}
 
-	if err := h.itemService.Update(c.Request().Context(), &item); err != nil {
-		log.Error("failed to update item", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update item")
+	item, err := h.itemService.Patch(c.Request().Context(), id, expectedVersion, patchType, doc)
+	if err != nil {
+		log.Error("failed to patch item", err)
+		return err
 	}
 
+	c.Response().Header().Set(headerETag, itemETag(item))
 	return c.JSON(http.StatusOK, item)
 }
 
@@ -75,40 +121,74 @@ func (h *ItemHandler) DeleteItem(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid item ID")
 	}
 
-	if err := h.itemService.Delete(c.Request().Context(), &domain.Item{ID: id}); err != nil {
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		log.Warn("unusable If-Match header")
+		return err
+	}
+
+	if err := h.itemService.Delete(c.Request().Context(), id, expectedVersion); err != nil {
 		log.Error("failed to delete item", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete item")
+		return err
 	}
 
 	return c.NoContent(http.StatusNoContent)
 }
 
+func (h *ItemHandler) RestoreItem(c echo.Context) error {
+	log := h.logger.WithContext(c.Request().Context())
+
+	id, err := getIDParam(c)
+	if err != nil {
+		log.With("error", err.Error()).Warn("invalid item id")
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid item ID")
+	}
+
+	item, err := h.itemService.Restore(c.Request().Context(), id)
+	if err != nil {
+		log.Error("failed to restore item", err)
+		return err
+	}
+
+	c.Response().Header().Set(headerETag, itemETag(item))
+	return c.JSON(http.StatusOK, item)
+}
+
 func (h *ItemHandler) GetItems(c echo.Context) error {
 	log := h.logger.WithContext(c.Request().Context())
 
-	userID := c.Request().Header.Get(constants.HeaderUserID)
+	userID := contextutils.UserIDFromContext(c.Request().Context())
 	if userID == "" {
-		log.Warn("user id is required")
-		return echo.NewHTTPError(http.StatusBadRequest, "userID is required")
+		log.Warn("authenticated user is required")
+		return domain.ErrUnauthenticated
+	}
+
+	query, err := parseItemQuery(c)
+	if err != nil {
+		log.With("error", err.Error()).Warn("invalid list query")
+		return err
 	}
 
 	queryall := c.QueryParam("all")
 	if queryall == "true" {
-		items, err := h.itemService.GetAll(c.Request().Context())
+		// Listing across users is admin-only; the service enforces it and
+		// also restricts the optional user_id filter to admins.
+		query.UserID = c.QueryParam("user_id")
+		page, err := h.itemService.GetAll(c.Request().Context(), query)
 		if err != nil {
 			log.Error("failed to fetch items", err)
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch items")
+			return err
 		}
-		return c.JSON(http.StatusOK, items)
+		return jsonWithETag(c, http.StatusOK, pageETag(page), page)
 	}
 
-	items, err := h.itemService.GetByUserID(c.Request().Context(), userID)
+	page, err := h.itemService.GetByUserID(c.Request().Context(), userID, query)
 	if err != nil {
 		log.Error("failed to fetch items", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch items")
+		return err
 	}
 
-	return c.JSON(http.StatusOK, items)
+	return jsonWithETag(c, http.StatusOK, pageETag(page), page)
 }
 
 func (h *ItemHandler) GetItem(c echo.Context) error {
@@ -123,37 +203,65 @@ func (h *ItemHandler) GetItem(c echo.Context) error {
 	item, err := h.itemService.GetByID(c.Request().Context(), id)
 	if err != nil {
 		log.Error("failed to fetch item", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch item")
+		return err
 	}
 
-	return c.JSON(http.StatusOK, item)
+	return jsonWithETag(c, http.StatusOK, itemETag(item), item)
 }
 
 func (h *ItemHandler) GetItemsByUserID(c echo.Context) error {
 	log := h.logger.WithContext(c.Request().Context())
 
-	userID := c.Request().Header.Get(constants.HeaderUserID)
-	if userID == "" {
-		log.Warn("user id is required")
-		return echo.NewHTTPError(http.StatusBadRequest, "userID is required")
+	userID := c.Param("user_id")
+
+	query, err := parseItemQuery(c)
+	if err != nil {
+		log.With("error", err.Error()).Warn("invalid list query")
+		return err
 	}
 
-	items, err := h.itemService.GetByUserID(c.Request().Context(), userID)
+	page, err := h.itemService.GetByUserID(c.Request().Context(), userID, query)
 	if err != nil {
 		log.Error("failed to fetch items by user id", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch items")
+		return err
+	}
+
+	return jsonWithETag(c, http.StatusOK, pageETag(page), page)
+}
+
+func (h *ItemHandler) GetDeletedItems(c echo.Context) error {
+	log := h.logger.WithContext(c.Request().Context())
+
+	query, err := parseItemQuery(c)
+	if err != nil {
+		log.With("error", err.Error()).Warn("invalid list query")
+		return err
 	}
+	query.UserID = c.QueryParam("user_id")
 
-	return c.JSON(http.StatusOK, items)
+	page, err := h.itemService.GetDeleted(c.Request().Context(), query)
+	if err != nil {
+		log.Error("failed to fetch trashed items", err)
+		return err
+	}
+
+	return jsonWithETag(c, http.StatusOK, pageETag(page), page)
 }
 
 func (h *ItemHandler) RegisterRoutes(e *echo.Group) {
 	itemGroup := e.Group("/items")
 	itemGroup.POST("", h.CreateItem)
-	itemGroup.PUT("", h.UpdateItem)
+	// The colon of /items:batch is escaped so it is not read as a parameter.
+	itemGroup.POST(`\:batch`, h.CreateItems)
+	itemGroup.PUT(`\:batch`, h.ReplaceItems)
+	itemGroup.DELETE(`\:batch`, h.DeleteItems)
+	itemGroup.PUT("/:id", h.ReplaceItem)
+	itemGroup.PATCH("/:id", h.PatchItem)
 	itemGroup.DELETE("/:id", h.DeleteItem)
 	itemGroup.GET("", h.GetItems)
+	itemGroup.GET("/trash", h.GetDeletedItems)
 	itemGroup.GET("/:id", h.GetItem)
+	itemGroup.POST("/:id/restore", h.RestoreItem)
 	itemGroup.GET("/user/:user_id", h.GetItemsByUserID)
 }
 
@@ -175,3 +283,64 @@ func getIDParam(c echo.Context) (int64, error) {
 
 	return id, nil
 }
+
+// getPatchType resolves the patch format from the Content-Type header.
+// Plain JSON bodies are treated as merge patches.
+func getPatchType(c echo.Context) (domain.PatchType, error) {
+	mediaType, _, err := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusUnsupportedMediaType, "Content-Type is required")
+	}
+
+	switch mediaType {
+	case string(domain.PatchTypeMerge), echo.MIMEApplicationJSON:
+		return domain.PatchTypeMerge, nil
+	case string(domain.PatchTypeJSON):
+		return domain.PatchTypeJSON, nil
+	default:
+		return "", echo.NewHTTPError(http.StatusUnsupportedMediaType,
+			"Content-Type must be application/merge-patch+json or application/json-patch+json")
+	}
+}
+
+// parseItemQuery builds a list query from the pagination, sorting and
+// filtering query parameters.
+func parseItemQuery(c echo.Context) (domain.ItemQuery, error) {
+	query := domain.ItemQuery{
+		Cursor:  c.QueryParam("cursor"),
+		SortBy:  domain.SortField(c.QueryParam("sort")),
+		SortDir: domain.SortDirection(c.QueryParam("order")),
+	}
+	query.TitlePrefix = c.QueryParam("title_prefix")
+
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > domain.MaxPageLimit {
+			return query, fmt.Errorf("%w: limit must be an integer between 1 and %d", domain.ErrInvalidQuery, domain.MaxPageLimit)
+		}
+		query.Limit = limit
+	}
+
+	timeParams := []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"created_after", &query.CreatedAfter},
+		{"created_before", &query.CreatedBefore},
+		{"updated_after", &query.UpdatedAfter},
+		{"updated_before", &query.UpdatedBefore},
+	}
+	for _, p := range timeParams {
+		raw := c.QueryParam(p.name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return query, fmt.Errorf("%w: %s must be an RFC 3339 timestamp", domain.ErrInvalidQuery, p.name)
+		}
+		*p.dst = &t
+	}
+
+	return query, nil
+}
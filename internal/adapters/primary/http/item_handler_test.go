@@ -0,0 +1,20 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestParseItemQueryRejectsLimit(t *testing.T) {
+	const want = "invalid query: limit must be an integer between 1 and 100"
+	for _, limit := range []string{"0", "101", "ten"} {
+		req := httptest.NewRequest(http.MethodGet, "/items?limit="+limit, nil)
+		_, err := parseItemQuery(echo.New().NewContext(req, httptest.NewRecorder()))
+		if problem := NewProblem(err); problem.Status != http.StatusBadRequest || problem.Detail != want {
+			t.Errorf("limit=%s: %d %q, want %d %q", limit, problem.Status, problem.Detail, http.StatusBadRequest, want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// bindItem decodes a JSON item payload. Unknown and mistyped members are
+// reported together with the item's own validation errors so clients get
+// every violation in one response.
+func bindItem(c echo.Context, item *domain.Item) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to read request body")
+	}
+	return decodeItem(body, item, "Request body")
+}
+
+// decodeItem decodes the JSON item payload body as bindItem does; subject
+// names the payload in errors.
+func decodeItem(body []byte, item *domain.Item, subject string) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(body, &members); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, subject+" must be a JSON object")
+	}
+
+	var verr domain.ValidationError
+
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !domain.IsItemField(name) {
+			verr.Add(name, domain.CodeUnknownField, "unknown field "+name)
+		}
+	}
+
+	if err := json.Unmarshal(body, item); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid request payload")
+		}
+		verr.Add(typeErr.Field, domain.CodeInvalidType, typeErr.Field+" must be of type "+typeErr.Type.String())
+	}
+
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+
+	// Fields that failed to decode are already reported once.
+	reported := make(map[string]bool, len(verr.Errors))
+	for _, fe := range verr.Errors {
+		reported[fe.Field] = true
+	}
+	var itemErr *domain.ValidationError
+	if errors.As(item.Validate(), &itemErr) {
+		for _, fe := range itemErr.Errors {
+			if !reported[fe.Field] {
+				verr.Errors = append(verr.Errors, fe)
+			}
+		}
+	}
+	return &verr
+}
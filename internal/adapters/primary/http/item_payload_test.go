@@ -0,0 +1,51 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+)
+
+func TestDecodeItem(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []domain.FieldError
+	}{
+		{"valid", `{"title":"t","description":"d"}`, nil},
+		{"every violation at once", `{"title":7,"colour":"red","description":"bell\u0007"}`, []domain.FieldError{
+			{Field: "colour", Code: domain.CodeUnknownField, Message: "unknown field colour"},
+			{Field: "title", Code: domain.CodeInvalidType, Message: "title must be of type string"},
+			{Field: "description", Code: domain.CodeInvalidCharacters, Message: "description must not contain control characters"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var item domain.Item
+			err := decodeItem([]byte(tt.body), &item, "Request body")
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("decodeItem returned %v", err)
+				}
+				return
+			}
+			var verr *domain.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("decodeItem returned %v, want a *domain.ValidationError", err)
+			}
+			if !reflect.DeepEqual(verr.Errors, tt.want) {
+				t.Errorf("decodeItem reported %+v, want %+v", verr.Errors, tt.want)
+			}
+		})
+	}
+
+	var he *echo.HTTPError
+	if err := decodeItem([]byte(`["title"]`), &domain.Item{}, "Request body"); !errors.As(err, &he) || he.Code != http.StatusBadRequest {
+		t.Errorf("decodeItem of an array returned %v, want a 400", err)
+	}
+}
@@ -0,0 +1,167 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxRedactableBody bounds the bytes buffered per body so it can be parsed
+// for redaction. Larger bodies are not logged.
+const maxRedactableBody = 1 << 20
+
+const redactedValue = "[REDACTED]"
+
+// bodyCapture buffers up to maxRedactableBody bytes of a body and counts
+// the rest.
+type bodyCapture struct {
+	buf  bytes.Buffer
+	size int64
+}
+
+func (b *bodyCapture) capture(p []byte) {
+	b.size += int64(len(p))
+	if room := maxRedactableBody - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(len(p), room)])
+	}
+}
+
+func (b *bodyCapture) complete() bool {
+	return b.size == int64(b.buf.Len())
+}
+
+// captureReader copies what the handler reads from the request body.
+type captureReader struct {
+	io.ReadCloser
+	body *bodyCapture
+}
+
+func (r *captureReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.body.capture(p[:n])
+	return n, err
+}
+
+// responseBodyWriter wraps http.ResponseWriter to capture the response body.
+// Capturing starts on the first write, once the status is known, and only
+// if capture approves of it.
+type responseBodyWriter struct {
+	http.ResponseWriter
+	body      *bodyCapture
+	status    int
+	capture   func(status int) bool
+	capturing *bool
+}
+
+func (w *responseBodyWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseBodyWriter) Write(p []byte) (int, error) {
+	if w.capturing == nil {
+		status := w.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		capturing := w.capture(status)
+		w.capturing = &capturing
+	}
+	if *w.capturing {
+		w.body.capture(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *responseBodyWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// isTextual reports whether a body of contentType is worth logging.
+func isTextual(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	return strings.Contains(contentType, "json") || strings.HasPrefix(contentType, "text/")
+}
+
+// renderBody returns the loggable form of a captured body: JSON with the
+// redacted fields masked, truncated to maxBytes. ok is false when the body
+// cannot be logged safely.
+func renderBody(body *bodyCapture, contentType string, redact map[string]bool, maxBytes int) (rendered string, truncated bool, ok bool) {
+	if body.size == 0 {
+		return "", false, false
+	}
+	if !isTextual(contentType) || !body.complete() {
+		return "", false, false
+	}
+
+	raw := body.buf.Bytes()
+	if len(redact) > 0 && strings.Contains(strings.ToLower(contentType), "json") {
+		var doc any
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			// Fields of a malformed document cannot be located.
+			return "", false, false
+		}
+		masked, err := json.Marshal(redactJSON(doc, redact))
+		if err != nil {
+			return "", false, false
+		}
+		raw = masked
+	}
+
+	if maxBytes > 0 && len(raw) > maxBytes {
+		return string(raw[:maxBytes]), true, true
+	}
+	return string(raw), false, true
+}
+
+// redactJSON replaces the values of the fields named in redact, compared
+// case-insensitively, at any depth. The value of a JSON Patch operation is
+// replaced as well when its path or from points into a redacted field.
+func redactJSON(value any, redact map[string]bool) any {
+	switch v := value.(type) {
+	case map[string]any:
+		if _, ok := v["value"]; ok && patchTargetsRedacted(v, redact) {
+			v["value"] = redactedValue
+		}
+		for key, field := range v {
+			if redact[strings.ToLower(key)] {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactJSON(field, redact)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactJSON(item, redact)
+		}
+	}
+	return value
+}
+
+// pointerUnescaper decodes a reference token of a JSON Pointer.
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// patchTargetsRedacted reports whether op is a JSON Patch operation whose
+// path or from has a redacted field among its reference tokens.
+func patchTargetsRedacted(op map[string]any, redact map[string]bool) bool {
+	if _, ok := op["op"].(string); !ok {
+		return false
+	}
+	for _, member := range []string{"path", "from"} {
+		pointer, ok := op[member].(string)
+		if !ok {
+			continue
+		}
+		for _, token := range strings.Split(pointer, "/") {
+			token = pointerUnescaper.Replace(token)
+			if redact[strings.ToLower(token)] {
+				return true
+			}
+		}
+	}
+	return false
+}
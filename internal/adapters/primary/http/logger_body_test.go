@@ -0,0 +1,63 @@
+package http
+
+import "testing"
+
+func TestRenderBodyRedacts(t *testing.T) {
+	redact := map[string]bool{"description": true, "secret": true}
+	tests := []struct {
+		name, contentType, body, want string
+	}{
+		{
+			"object fields at any depth",
+			"application/json",
+			`{"title":"t","description":"d","meta":{"Secret":"s"}}`,
+			`{"description":"[REDACTED]","meta":{"Secret":"[REDACTED]"},"title":"t"}`,
+		},
+		{
+			"JSON Patch values of redacted fields",
+			"application/json-patch+json",
+			`[{"op":"replace","path":"/description","value":"d"},{"op":"add","path":"/meta/secret","value":"s"},{"op":"replace","path":"/title","value":"t"}]`,
+			`[{"op":"replace","path":"/description","value":"[REDACTED]"},{"op":"add","path":"/meta/secret","value":"[REDACTED]"},{"op":"replace","path":"/title","value":"t"}]`,
+		},
+		{
+			"JSON Patch tests against redacted fields",
+			"application/json-patch+json",
+			`[{"op":"test","path":"/Description","value":"d"},{"op":"copy","from":"/description","path":"/title"}]`,
+			`[{"op":"test","path":"/Description","value":"[REDACTED]"},{"from":"/description","op":"copy","path":"/title"}]`,
+		},
+		{
+			"JSON Patch documents replaced whole",
+			"application/json-patch+json",
+			`[{"op":"replace","path":"","value":{"description":"d","title":"t"}}]`,
+			`[{"op":"replace","path":"","value":{"description":"[REDACTED]","title":"t"}}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bodyCapture
+			body.capture([]byte(tt.body))
+			got, _, ok := renderBody(&body, tt.contentType, redact, 0)
+			if !ok || got != tt.want {
+				t.Errorf("renderBody = %q, %v, want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactQuery(t *testing.T) {
+	redact := map[string]bool{"token": true, "access_token": true}
+	tests := []struct {
+		query, want string
+	}{
+		{"q=milk&limit=10", "q=milk&limit=10"},
+		{"token=abc&q=milk", "token=[REDACTED]&q=milk"},
+		{"q=milk&Access_Token=abc&access%5Ftoken=def", "q=milk&Access_Token=[REDACTED]&access%5Ftoken=[REDACTED]"},
+		{"token", "token"},
+		{"token=%zz", "token=[REDACTED]"},
+	}
+	for _, tt := range tests {
+		if got := redactQuery(tt.query, redact); got != tt.want {
+			t.Errorf("redactQuery(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
@@ -1,9 +1,9 @@
 package http
 
 import (
-	"bytes"
-	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/krisadabig/supreme-ms-item/internal/constants"
@@ -14,20 +14,83 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-// responseBodyWriter wraps http.ResponseWriter to capture the response body.
-type responseBodyWriter struct {
-	io.Writer
-	http.ResponseWriter
-	body *bytes.Buffer
+// BodyLogMode selects the requests whose bodies are logged.
+type BodyLogMode string
+
+const (
+	BodyLogOff    BodyLogMode = "off"
+	BodyLogErrors BodyLogMode = "errors"
+	BodyLogAlways BodyLogMode = "always"
+)
+
+// LoggerConfig controls what the request logger records.
+type LoggerConfig struct {
+	// Body selects when request and response bodies are logged. Errors
+	// logs them for responses with a 4xx or 5xx status only.
+	Body BodyLogMode
+	// MaxBodyBytes truncates logged bodies; zero means no limit.
+	MaxBodyBytes int
+	// RedactFields names JSON fields, at any depth, and query parameters
+	// whose values are masked in logs. Names are compared case-insensitively.
+	RedactFields []string
+	// ExcludePaths lists request paths that are not logged. An entry ending
+	// in "*" matches every path with that prefix.
+	ExcludePaths []string
+}
+
+// excluded reports whether requests to path are not logged.
+func (cfg LoggerConfig) excluded(path string) bool {
+	for _, pattern := range cfg.ExcludePaths {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		} else if path == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// redactQuery masks the values of the query parameters named in redact,
+// keeping the rest of the raw query as sent.
+func redactQuery(query string, redact map[string]bool) string {
+	if len(redact) == 0 {
+		return query
+	}
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		key, _, hasValue := strings.Cut(param, "=")
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			name = key
+		}
+		if hasValue && redact[strings.ToLower(name)] {
+			params[i] = key + "=" + redactedValue
+		}
+	}
+	return strings.Join(params, "&")
 }
 
-func (rbw *responseBodyWriter) Write(b []byte) (int, error) {
-	rbw.body.Write(b)
-	return rbw.Writer.Write(b)
+// logBody reports whether the bodies of a request answered with status are logged.
+func (cfg LoggerConfig) logBody(status int) bool {
+	switch cfg.Body {
+	case BodyLogAlways:
+		return true
+	case BodyLogErrors:
+		return status >= http.StatusBadRequest
+	default:
+		return false
+	}
 }
 
 // Logger returns a middleware that logs HTTP requests and responses.
-func Logger(log ports.Logger) echo.MiddlewareFunc {
+func Logger(log ports.Logger, cfg LoggerConfig) echo.MiddlewareFunc {
+	redact := make(map[string]bool, len(cfg.RedactFields))
+	for _, field := range cfg.RedactFields {
+		redact[strings.ToLower(field)] = true
+	}
+
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			req := c.Request()
@@ -39,28 +102,48 @@ func Logger(log ports.Logger) echo.MiddlewareFunc {
 				requestID = uuid.NewString()
 			}
 
-			requestLogger := log.
-				With("request_id", requestID).
-				With("method", req.Method).
-				With("path", req.URL.Path).
-				With("remote_ip", c.RealIP())
-
 			ctx := contextutils.ContextWithRequestID(req.Context(), requestID)
-			c.SetRequest(req.WithContext(ctx))
+			req = req.WithContext(ctx)
+			c.SetRequest(req)
 
-			// Capture response body for logging
 			res := c.Response()
-			rbw := &responseBodyWriter{
-				Writer:         res.Writer,
-				body:           &bytes.Buffer{},
-				ResponseWriter: res.Writer,
-			}
-			res.Writer = rbw
 
 			// Ensure request ID is echoed back to the client
 			res.Header().Set(constants.HeaderCorrelationID, requestID)
 
+			if cfg.excluded(req.URL.Path) {
+				if err := next(c); err != nil {
+					c.Error(err)
+				}
+				return nil
+			}
+
+			// WithContext adds the request ID and the trace of the request.
+			requestLogger := log.WithContext(ctx).
+				With("method", req.Method).
+				With("path", req.URL.Path).
+				With("remote_ip", c.RealIP())
+
+			// Capture the bodies the handler reads and writes. Bodies are
+			// buffered only when they may be logged.
+			var reqBody, resBody bodyCapture
+			if cfg.Body != BodyLogOff && cfg.Body != "" {
+				if req.Body != nil && req.Body != http.NoBody {
+					req.Body = &captureReader{ReadCloser: req.Body, body: &reqBody}
+				}
+				res.Writer = &responseBodyWriter{
+					ResponseWriter: res.Writer,
+					body:           &resBody,
+					capture:        cfg.logBody,
+				}
+			}
+
+			// Render the error here, rather than after the middleware chain,
+			// so the logged status is the one sent to the client.
 			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
 
 			latency := time.Since(start)
 			status := res.Status
@@ -71,11 +154,25 @@ func Logger(log ports.Logger) echo.MiddlewareFunc {
 			logEntry := requestLogger.
 				With("status", status).
 				With("latency_ms", latency.Milliseconds()).
-				With("response_size", res.Size).
-				With("response_body", rbw.body.String())
+				With("response_size", res.Size)
 
 			if query := req.URL.RawQuery; query != "" {
-				logEntry = logEntry.With("query", query)
+				logEntry = logEntry.With("query", redactQuery(query, redact))
+			}
+
+			if cfg.logBody(status) {
+				if body, truncated, ok := renderBody(&reqBody, req.Header.Get(echo.HeaderContentType), redact, cfg.MaxBodyBytes); ok {
+					logEntry = logEntry.With("request_body", body)
+					if truncated {
+						logEntry = logEntry.With("request_body_truncated", true)
+					}
+				}
+				if body, truncated, ok := renderBody(&resBody, res.Header().Get(echo.HeaderContentType), redact, cfg.MaxBodyBytes); ok {
+					logEntry = logEntry.With("response_body", body)
+					if truncated {
+						logEntry = logEntry.With("response_body_truncated", true)
+					}
+				}
 			}
 
 			if err != nil {
@@ -90,7 +187,7 @@ func Logger(log ports.Logger) echo.MiddlewareFunc {
 
 			logEntry.Info("")
 
-			return err
+			return nil
 		}
 	}
 }
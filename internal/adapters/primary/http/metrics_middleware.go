@@ -0,0 +1,50 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+
+	"github.com/labstack/echo/v4"
+)
+
+// unmatchedRoute labels requests that did not match any route, so arbitrary
+// paths cannot blow up the label cardinality.
+const unmatchedRoute = "unmatched"
+
+// routeOf returns the template of the route c matched, as clients see it:
+// colons escaped so they are not read as parameters, as in /items\:batch,
+// lose their backslash.
+func routeOf(c echo.Context) string {
+	route := c.Path()
+	if route == "" {
+		return unmatchedRoute
+	}
+	return strings.ReplaceAll(route, `\:`, ":")
+}
+
+// Metrics returns a middleware that records the count, latency and
+// in-flight number of HTTP requests, labelled by route template.
+func Metrics(m ports.HTTPMetrics) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			m.RequestStarted()
+
+			// Render the error here so the recorded status is the one sent
+			// to the client.
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			m.RequestFinished(c.Request().Method, routeOf(c), status, time.Since(start))
+			return nil
+		}
+	}
+}
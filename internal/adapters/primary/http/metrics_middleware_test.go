@@ -0,0 +1,41 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestRouteOf(t *testing.T) {
+	e := echo.New()
+	var route string
+	record := func(c echo.Context) error {
+		route = routeOf(c)
+		return c.NoContent(http.StatusOK)
+	}
+	e.POST(`/items\:batch`, record)
+	e.PUT("/items/:id", record)
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			route = routeOf(c)
+			return next(c)
+		}
+	})
+
+	tests := []struct {
+		method, path, want string
+	}{
+		{http.MethodPost, "/items:batch", "/items:batch"},
+		{http.MethodPut, "/items/7", "/items/:id"},
+		{http.MethodGet, "/nowhere", unmatchedRoute},
+	}
+	for _, tt := range tests {
+		route = ""
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+		if route != tt.want {
+			t.Errorf("%s %s: route %q, want %q", tt.method, tt.path, route, tt.want)
+		}
+	}
+}
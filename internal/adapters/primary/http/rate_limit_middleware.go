@@ -0,0 +1,97 @@
+package http
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+)
+
+// errRateLimited is returned when a client exceeds its request rate.
+var errRateLimited = errors.New("rate limit exceeded")
+
+// Limiters of clients idle for longer than clientIdleTimeout are dropped.
+const (
+	clientIdleTimeout = 3 * time.Minute
+	clientSweepPeriod = time.Minute
+)
+
+type rateClient struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimiter limits the request rate of each client IP with a token
+// bucket. Its settings can be changed while the server runs.
+type RateLimiter struct {
+	mu        sync.Mutex
+	enabled   bool
+	limit     rate.Limit
+	burst     int
+	clients   map[string]*rateClient
+	lastSweep time.Time
+}
+
+// NewRateLimiter allows each client requestsPerSecond on average, with bursts
+// of up to burst requests.
+func NewRateLimiter(enabled bool, requestsPerSecond float64, burst int) *RateLimiter {
+	r := &RateLimiter{clients: make(map[string]*rateClient)}
+	r.Configure(enabled, requestsPerSecond, burst)
+	return r
+}
+
+// Configure changes the limits, including those of clients already seen.
+func (r *RateLimiter) Configure(enabled bool, requestsPerSecond float64, burst int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.enabled = enabled
+	r.limit = rate.Limit(requestsPerSecond)
+	r.burst = burst
+	for _, c := range r.clients {
+		c.limiter.SetLimit(r.limit)
+		c.limiter.SetBurst(r.burst)
+	}
+}
+
+func (r *RateLimiter) allow(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.enabled {
+		return true
+	}
+
+	now := time.Now()
+	if now.Sub(r.lastSweep) > clientSweepPeriod {
+		for k, c := range r.clients {
+			if now.Sub(c.lastSeen) > clientIdleTimeout {
+				delete(r.clients, k)
+			}
+		}
+		r.lastSweep = now
+	}
+
+	c, ok := r.clients[key]
+	if !ok {
+		c = &rateClient{limiter: rate.NewLimiter(r.limit, r.burst)}
+		r.clients[key] = c
+	}
+	c.lastSeen = now
+	return c.limiter.AllowN(now, 1)
+}
+
+// RateLimit returns a middleware rejecting requests of clients that exceed
+// the limits of limiter with 429 Too Many Requests.
+func RateLimit(limiter *RateLimiter) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !limiter.allow(c.RealIP()) {
+				return errRateLimited
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package http
+
+import (
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+
+	"github.com/labstack/echo/v4"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
+)
+
+// Recover returns a middleware that turns a panic further down the chain
+// into an error, logged with its stack, so the client gets a 500 and the
+// middleware around it sees the request finish.
+func Recover(log ports.Logger) echo.MiddlewareFunc {
+	return echoMiddleware.RecoverWithConfig(echoMiddleware.RecoverConfig{
+		LogErrorFunc: func(c echo.Context, err error, stack []byte) error {
+			req := c.Request()
+			log.WithContext(req.Context()).
+				With("method", req.Method).
+				With("path", req.URL.Path).
+				With("stack", string(stack)).
+				Error("recovered from panic", err)
+			return err
+		},
+		// Return the error rather than render it, so the middleware around
+		// this one renders it and records the status.
+		DisableErrorHandler: true,
+	})
+}
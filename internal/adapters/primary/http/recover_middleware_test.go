@@ -0,0 +1,45 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/logger"
+)
+
+// recordingMetrics records the requests reported to ports.HTTPMetrics.
+type recordingMetrics struct {
+	inFlight int
+	statuses []int
+}
+
+func (m *recordingMetrics) RequestStarted() { m.inFlight++ }
+
+func (m *recordingMetrics) RequestFinished(method, route string, status int, duration time.Duration) {
+	m.inFlight--
+	m.statuses = append(m.statuses, status)
+}
+
+func TestRecover(t *testing.T) {
+	log := logger.New(logger.WithOutput(io.Discard))
+	metrics := &recordingMetrics{}
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler(log)
+	e.Use(Metrics(metrics), Recover(log))
+	e.GET("/panic", func(echo.Context) error { panic("boom") })
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if metrics.inFlight != 0 || len(metrics.statuses) != 1 || metrics.statuses[0] != http.StatusInternalServerError {
+		t.Errorf("metrics recorded %v with %d in flight, want one 500 and none in flight", metrics.statuses, metrics.inFlight)
+	}
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/labstack/echo/v4"
+)
+
+// W3C Trace Context headers.
+const (
+	headerTraceparent = "traceparent"
+	headerTracestate  = "tracestate"
+)
+
+// Tracing returns a middleware that continues the trace of an incoming W3C
+// traceparent header, or starts a new one, and wraps the request in a server
+// span. The traceparent of the span is echoed back to the client.
+func Tracing(provider trace.TracerProvider, propagator propagation.TextMapPropagator) echo.MiddlewareFunc {
+	tracer := provider.Tracer("github.com/krisadabig/supreme-ms-item/internal/adapters/primary/http")
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			route := routeOf(c)
+
+			ctx := propagator.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+			ctx, span := tracer.Start(ctx, req.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.request.method", req.Method),
+					attribute.String("http.route", route),
+					attribute.String("url.path", req.URL.Path),
+					attribute.String("client.address", c.RealIP()),
+				),
+			)
+			defer span.End()
+
+			c.SetRequest(req.WithContext(ctx))
+			propagator.Inject(ctx, propagation.HeaderCarrier(c.Response().Header()))
+
+			// Render the error here so the span records the status sent to
+			// the client.
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			span.SetAttributes(attribute.Int("http.response.status_code", status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/logger"
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler(logger.New(logger.WithOutput(io.Discard)))
+	e.Use(Tracing(provider, propagation.TraceContext{}))
+	var handlerSpan trace.SpanContext
+	e.GET("/items/:id", func(c echo.Context) error {
+		handlerSpan = trace.SpanContextFromContext(c.Request().Context())
+		if c.Param("id") == "0" {
+			return domain.ErrUnavailable
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantCode   codes.Code
+	}{
+		{"/items/7", http.StatusOK, codes.Unset},
+		{"/items/0", http.StatusServiceUnavailable, codes.Error},
+	}
+	for _, tt := range tests {
+		recorder.Reset()
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set(headerTraceparent, "00-"+traceID+"-00f067aa0ba902b7-01")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		spans := recorder.Ended()
+		if len(spans) != 1 {
+			t.Fatalf("%s: %d spans ended, want 1", tt.path, len(spans))
+		}
+		span := spans[0]
+		if span.Name() != "GET /items/:id" || span.SpanKind() != trace.SpanKindServer {
+			t.Errorf("%s: span %q of kind %v", tt.path, span.Name(), span.SpanKind())
+		}
+		if span.SpanContext().TraceID().String() != traceID || span.Parent().SpanID().String() != "00f067aa0ba902b7" {
+			t.Errorf("%s: span does not continue the incoming trace", tt.path)
+		}
+		if handlerSpan.SpanID() != span.SpanContext().SpanID() {
+			t.Errorf("%s: handler context does not carry the server span", tt.path)
+		}
+		if !strings.Contains(rec.Header().Get(headerTraceparent), traceID) {
+			t.Errorf("%s: traceparent %q does not echo the trace", tt.path, rec.Header().Get(headerTraceparent))
+		}
+		if span.Status().Code != tt.wantCode {
+			t.Errorf("%s: span status %v, want %v", tt.path, span.Status().Code, tt.wantCode)
+		}
+		want := attribute.Int("http.response.status_code", tt.wantStatus)
+		found := false
+		for _, attr := range span.Attributes() {
+			found = found || attr == want
+		}
+		if !found || rec.Code != tt.wantStatus {
+			t.Errorf("%s: span attributes %v and status %d, want %d", tt.path, span.Attributes(), rec.Code, tt.wantStatus)
+		}
+	}
+}
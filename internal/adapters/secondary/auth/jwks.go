@@ -0,0 +1,254 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// minJWKSRefetch bounds how often a key set served from a URL is fetched,
+// whether a fetch succeeded or failed, outside of startup.
+const minJWKSRefetch = time.Minute
+
+var errUnknownKey = errors.New("unknown signing key")
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// keySet holds the public keys of a JSON Web Key Set, loaded either once from
+// a local file or lazily (and periodically) from a URL. Concurrent refreshes
+// share a single fetch.
+type keySet struct {
+	file            string
+	url             string
+	refreshInterval time.Duration
+	client          *http.Client
+	refreshes       singleflight.Group
+
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+}
+
+func newKeySet(ctx context.Context, file, url string, refreshInterval time.Duration) (*keySet, error) {
+	ks := &keySet{
+		file:            file,
+		url:             url,
+		refreshInterval: refreshInterval,
+		client:          &http.Client{Timeout: 10 * time.Second},
+	}
+	if err := ks.load(ctx); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// key returns the public key for kid. A key set served from a URL is
+// refreshed when it is stale, in the background while its keys are still
+// served, or when it does not know kid yet.
+func (ks *keySet) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	ks.mu.RLock()
+	key, ok := ks.lookup(kid)
+	stale := ks.refreshInterval > 0 && time.Since(ks.fetchedAt) > ks.refreshInterval
+	ks.mu.RUnlock()
+
+	if ks.url != "" && ok && stale {
+		ks.refreshes.DoChan("", func() (any, error) {
+			return nil, ks.refresh(context.WithoutCancel(ctx))
+		})
+	}
+	if ks.url != "" && !ok {
+		_, err, _ := ks.refreshes.Do("", func() (any, error) {
+			return nil, ks.refresh(context.WithoutCancel(ctx))
+		})
+		if err != nil {
+			return nil, err
+		}
+		ks.mu.RLock()
+		key, ok = ks.lookup(kid)
+		ks.mu.RUnlock()
+	}
+
+	if !ok {
+		return nil, errUnknownKey
+	}
+	return key, nil
+}
+
+// refresh loads the key set unless it was attempted less than
+// minJWKSRefetch ago, so an unreachable URL is not retried on every
+// request. Callers share a refresh in flight through ks.refreshes.
+func (ks *keySet) refresh(ctx context.Context) error {
+	ks.mu.RLock()
+	due := time.Since(ks.attemptedAt) > minJWKSRefetch
+	ks.mu.RUnlock()
+	if !due {
+		return nil
+	}
+	return ks.load(ctx)
+}
+
+// lookup must be called with ks.mu held. An empty kid is accepted only when
+// the set contains a single key.
+func (ks *keySet) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(ks.keys) == 1 {
+		for _, key := range ks.keys {
+			return key, true
+		}
+	}
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+// load replaces the keys with those of the file or URL. The attempt is
+// recorded even when it fails.
+func (ks *keySet) load(ctx context.Context) error {
+	ks.mu.Lock()
+	ks.attemptedAt = time.Now()
+	ks.mu.Unlock()
+
+	var (
+		raw []byte
+		err error
+	)
+	if ks.url != "" {
+		raw, err = ks.fetch(ctx)
+	} else {
+		raw, err = os.ReadFile(ks.file)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load jwks: %w", err)
+	}
+
+	keys, err := parseJWKS(raw)
+	if err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.fetchedAt = time.Now()
+	ks.mu.Unlock()
+	return nil
+}
+
+func (ks *keySet) fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ks.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ks.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+func parseJWKS(raw []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return nil, fmt.Errorf("invalid jwks document: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwk %q: %w", k.Kid, err)
+		}
+		if key != nil {
+			keys[k.Kid] = key
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwks contains no usable signing keys")
+	}
+	return keys, nil
+}
+
+// publicKey decodes RSA and EC keys; other key types are skipped.
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, errors.New("rsa exponent too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid base64url integer")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
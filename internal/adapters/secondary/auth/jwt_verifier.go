@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+// Config holds the settings of the JWT verifier.
+type Config struct {
+	// HS256Secret enables HS256 tokens signed with a shared secret.
+	HS256Secret string
+	// JWKSFile and JWKSURL enable RS256/ES256 tokens verified with the
+	// keys of a JSON Web Key Set. At most one of them may be set.
+	JWKSFile            string
+	JWKSURL             string
+	JWKSRefreshInterval time.Duration
+	// Issuer and Audience, when set, must match the iss and aud claims.
+	Issuer   string
+	Audience string
+	// Leeway is the clock skew tolerated when checking exp, nbf and iat.
+	Leeway time.Duration
+	// RolesClaim is the claim holding the user's roles, either a list or a
+	// space-separated string. Nested claims use dots, e.g. "realm_access.roles".
+	RolesClaim string
+	// AdminSubjects lists token subjects that are always granted the admin role.
+	AdminSubjects []string
+}
+
+// jwtVerifier implements ports.TokenVerifier for JSON Web Tokens.
+type jwtVerifier struct {
+	secret        []byte
+	keys          *keySet
+	parser        *jwt.Parser
+	rolesClaim    string
+	adminSubjects []string
+}
+
+// NewJWTVerifier creates a token verifier from cfg. At least one of the
+// shared secret or a key set must be configured.
+func NewJWTVerifier(ctx context.Context, cfg Config) (ports.TokenVerifier, error) {
+	if cfg.JWKSFile != "" && cfg.JWKSURL != "" {
+		return nil, errors.New("auth: jwks_file and jwks_url are mutually exclusive")
+	}
+
+	v := &jwtVerifier{
+		rolesClaim:    cfg.RolesClaim,
+		adminSubjects: cfg.AdminSubjects,
+	}
+	if v.rolesClaim == "" {
+		v.rolesClaim = "roles"
+	}
+	var methods []string
+
+	if cfg.HS256Secret != "" {
+		v.secret = []byte(cfg.HS256Secret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	if cfg.JWKSFile != "" || cfg.JWKSURL != "" {
+		keys, err := newKeySet(ctx, cfg.JWKSFile, cfg.JWKSURL, cfg.JWKSRefreshInterval)
+		if err != nil {
+			return nil, fmt.Errorf("auth: %w", err)
+		}
+		v.keys = keys
+		methods = append(methods, jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg())
+	}
+
+	if len(methods) == 0 {
+		return nil, errors.New("auth: no signing key configured, set hs256_secret, jwks_file or jwks_url")
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	v.parser = jwt.NewParser(opts...)
+
+	return v, nil
+}
+
+func (v *jwtVerifier) Verify(ctx context.Context, token string) (*domain.User, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.keyFunc(ctx)); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidToken, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", domain.ErrInvalidToken)
+	}
+
+	user := &domain.User{
+		ID:     subject,
+		Roles:  stringList(claimAt(claims, v.rolesClaim)),
+		Scopes: stringList(claims["scope"]),
+	}
+	if len(user.Scopes) == 0 {
+		user.Scopes = stringList(claims["scp"])
+	}
+	if slices.Contains(v.adminSubjects, subject) && !user.HasRole(domain.RoleAdmin) {
+		user.Roles = append(user.Roles, domain.RoleAdmin)
+	}
+
+	return user, nil
+}
+
+// claimAt resolves a dot-separated claim path.
+func claimAt(claims jwt.MapClaims, path string) any {
+	var value any = map[string]any(claims)
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = obj[key]
+	}
+	return value
+}
+
+// stringList reads a claim that is either a list of strings or a
+// space-separated string.
+func stringList(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+func (v *jwtVerifier) keyFunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (any, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			if v.secret == nil {
+				return nil, jwt.ErrTokenUnverifiable
+			}
+			return v.secret, nil
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+			if v.keys == nil {
+				return nil, jwt.ErrTokenUnverifiable
+			}
+			kid, _ := token.Header["kid"].(string)
+			return v.keys.key(ctx, kid)
+		default:
+			return nil, jwt.ErrTokenUnverifiable
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+// claims returns valid claims for subject alice, changed by edit.
+func claims(edit func(jwt.MapClaims)) jwt.MapClaims {
+	c := jwt.MapClaims{
+		"sub": "alice",
+		"iss": "https://issuer.example",
+		"aud": "items",
+		"iat": time.Now().Add(-time.Minute).Unix(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	if edit != nil {
+		edit(c)
+	}
+	return c
+}
+
+func signHS256(t *testing.T, c jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return token
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, c jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, c)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return key
+}
+
+// jwksDocument returns a JWKS holding the public halves of keys by kid.
+func jwksDocument(t *testing.T, keys map[string]*rsa.PrivateKey) []byte {
+	t.Helper()
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	for kid, key := range keys {
+		set.Keys = append(set.Keys, jwk{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	raw, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("encode jwks: %v", err)
+	}
+	return raw
+}
+
+// jwksServer serves a JWKS that can be replaced, or made to fail, and
+// counts how often it is fetched.
+type jwksServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	doc     []byte
+	failing bool
+	fetches atomic.Int32
+}
+
+func newJWKSServer(t *testing.T, doc []byte) *jwksServer {
+	s := &jwksServer{doc: doc}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write(s.doc)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) serve(doc []byte, failing bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.doc, s.failing = doc, failing
+}
+
+func TestVerifyHS256(t *testing.T) {
+	v, err := NewJWTVerifier(context.Background(), Config{
+		HS256Secret:   testSecret,
+		Issuer:        "https://issuer.example",
+		Audience:      "items",
+		AdminSubjects: []string{"root"},
+	})
+	if err != nil {
+		t.Fatalf("NewJWTVerifier: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"valid", signHS256(t, claims(nil)), false},
+		{"expired", signHS256(t, claims(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() })), true},
+		{"without expiry", signHS256(t, claims(func(c jwt.MapClaims) { delete(c, "exp") })), true},
+		{"issued in the future", signHS256(t, claims(func(c jwt.MapClaims) { c["iat"] = time.Now().Add(time.Hour).Unix() })), true},
+		{"other issuer", signHS256(t, claims(func(c jwt.MapClaims) { c["iss"] = "https://evil.example" })), true},
+		{"other audience", signHS256(t, claims(func(c jwt.MapClaims) { c["aud"] = "billing" })), true},
+		{"without subject", signHS256(t, claims(func(c jwt.MapClaims) { delete(c, "sub") })), true},
+		{"other secret", func() string {
+			token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims(nil)).SignedString([]byte("another secret of 32 bytes......"))
+			return token
+		}(), true},
+		{"unsigned", func() string {
+			token, _ := jwt.NewWithClaims(jwt.SigningMethodNone, claims(nil)).SignedString(jwt.UnsafeAllowNoneSignatureType)
+			return token
+		}(), true},
+		{"garbage", "not.a.token", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := v.Verify(context.Background(), tt.token)
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrInvalidToken) {
+					t.Errorf("Verify returned %v, want ErrInvalidToken", err)
+				}
+				return
+			}
+			if err != nil || user.ID != "alice" {
+				t.Errorf("Verify returned %+v, %v, want alice", user, err)
+			}
+		})
+	}
+}
+
+func TestVerifyRolesAndScopes(t *testing.T) {
+	v, err := NewJWTVerifier(context.Background(), Config{
+		HS256Secret:   testSecret,
+		RolesClaim:    "realm_access.roles",
+		AdminSubjects: []string{"root"},
+	})
+	if err != nil {
+		t.Fatalf("NewJWTVerifier: %v", err)
+	}
+
+	user, err := v.Verify(context.Background(), signHS256(t, claims(func(c jwt.MapClaims) {
+		c["realm_access"] = map[string]any{"roles": []any{"editor"}}
+		c["scope"] = "items:read items:write"
+	})))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if !slices.Equal(user.Roles, []string{"editor"}) || !slices.Equal(user.Scopes, []string{"items:read", "items:write"}) {
+		t.Errorf("Verify returned roles %v and scopes %v", user.Roles, user.Scopes)
+	}
+
+	root, err := v.Verify(context.Background(), signHS256(t, claims(func(c jwt.MapClaims) { c["sub"] = "root" })))
+	if err != nil || !root.IsAdmin() {
+		t.Errorf("Verify of an admin subject returned %+v, %v, want an admin", root, err)
+	}
+}
+
+func TestVerifyRejectsAlgorithmConfusion(t *testing.T) {
+	key := newRSAKey(t)
+	server := newJWKSServer(t, jwksDocument(t, map[string]*rsa.PrivateKey{"k1": key}))
+	v, err := NewJWTVerifier(context.Background(), Config{JWKSURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewJWTVerifier: %v", err)
+	}
+
+	// An HS256 token keyed with the public key, which attackers know, must
+	// not pass for one signed with the private key.
+	public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("encode public key: %v", err)
+	}
+	hs := jwt.NewWithClaims(jwt.SigningMethodHS256, claims(nil))
+	hs.Header["kid"] = "k1"
+	forged, err := hs.SignedString(public)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), forged); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Verify of an HS256 token without a secret returned %v, want ErrInvalidToken", err)
+	}
+
+	if _, err := v.Verify(context.Background(), signRS256(t, key, "k1", claims(nil))); err != nil {
+		t.Errorf("Verify of an RS256 token: %v", err)
+	}
+}
+
+func TestVerifyUnknownKeyRefetches(t *testing.T) {
+	old, rotated := newRSAKey(t), newRSAKey(t)
+	server := newJWKSServer(t, jwksDocument(t, map[string]*rsa.PrivateKey{"old": old}))
+	v, err := NewJWTVerifier(context.Background(), Config{JWKSURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewJWTVerifier: %v", err)
+	}
+	ks := v.(*jwtVerifier).keys
+	token := signRS256(t, rotated, "new", claims(nil))
+
+	// Right after a fetch an unknown kid is rejected without another one.
+	server.serve(jwksDocument(t, map[string]*rsa.PrivateKey{"old": old, "new": rotated}), false)
+	if _, err := v.Verify(context.Background(), token); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Verify with an unknown kid returned %v, want ErrInvalidToken", err)
+	}
+	if n := server.fetches.Load(); n != 1 {
+		t.Errorf("key set fetched %d times, want 1", n)
+	}
+
+	// Later, concurrent requests for the new kid share a single fetch.
+	ks.attemptedAt = time.Now().Add(-2 * minJWKSRefetch)
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := v.Verify(context.Background(), token); err != nil {
+				t.Errorf("Verify with a rotated key: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := server.fetches.Load(); n != 2 {
+		t.Errorf("key set fetched %d times, want 2", n)
+	}
+}
+
+func TestKeySetBacksOffAfterFailedFetch(t *testing.T) {
+	key := newRSAKey(t)
+	server := newJWKSServer(t, jwksDocument(t, map[string]*rsa.PrivateKey{"k1": key}))
+	ks, err := newKeySet(context.Background(), "", server.URL, time.Hour)
+	if err != nil {
+		t.Fatalf("newKeySet: %v", err)
+	}
+	server.serve(nil, true)
+
+	ks.attemptedAt = time.Now().Add(-2 * minJWKSRefetch)
+	if _, err := ks.key(context.Background(), "k2"); err == nil {
+		t.Error("key of an unknown kid succeeded while the URL fails")
+	}
+	if _, err := ks.key(context.Background(), "k2"); !errors.Is(err, errUnknownKey) {
+		t.Errorf("key right after a failed fetch returned %v, want errUnknownKey", err)
+	}
+	if n := server.fetches.Load(); n != 2 {
+		t.Errorf("key set fetched %d times, want 2", n)
+	}
+	if _, err := ks.key(context.Background(), "k1"); err != nil {
+		t.Errorf("key of a known kid after a failed fetch: %v", err)
+	}
+}
+
+func TestKeySetServesStaleKeysWhileRefreshing(t *testing.T) {
+	key := newRSAKey(t)
+	server := newJWKSServer(t, jwksDocument(t, map[string]*rsa.PrivateKey{"k1": key}))
+	ks, err := newKeySet(context.Background(), "", server.URL, time.Hour)
+	if err != nil {
+		t.Fatalf("newKeySet: %v", err)
+	}
+
+	ks.mu.Lock()
+	ks.fetchedAt = time.Now().Add(-2 * time.Hour)
+	ks.attemptedAt = ks.fetchedAt
+	ks.mu.Unlock()
+	if _, err := ks.key(context.Background(), "k1"); err != nil {
+		t.Fatalf("key of a stale set: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		ks.mu.RLock()
+		fresh := time.Since(ks.fetchedAt) < time.Hour
+		ks.mu.RUnlock()
+		if fresh {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("stale key set was not refreshed in the background")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := server.fetches.Load(); n != 2 {
+		t.Errorf("key set fetched %d times, want 2", n)
+	}
+}
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+var _ ports.LogLevelController = (*Level)(nil)
+
+// Level is a minimum log level shared by a logger and all of its children.
+// It can be changed while the application runs.
+type Level struct {
+	v atomic.Int32
+}
+
+// NewLevel returns a Level initialised to level.
+func NewLevel(level zerolog.Level) *Level {
+	l := &Level{}
+	l.Set(level)
+	return l
+}
+
+// Set changes the minimum level of every logger sharing l.
+func (l *Level) Set(level zerolog.Level) {
+	l.v.Store(int32(level))
+}
+
+// Get returns the current minimum level.
+func (l *Level) Get() zerolog.Level {
+	return zerolog.Level(l.v.Load())
+}
+
+func (l *Level) String() string {
+	return l.Get().String()
+}
+
+// Level implements ports.LogLevelController.
+func (l *Level) Level() string {
+	return l.String()
+}
+
+// SetLevel implements ports.LogLevelController.
+func (l *Level) SetLevel(name string) error {
+	level, err := zerolog.ParseLevel(name)
+	if err != nil || level == zerolog.NoLevel {
+		return fmt.Errorf("unknown log level %q", name)
+	}
+	l.Set(level)
+	return nil
+}
+
+func (l *Level) enabled(level zerolog.Level) bool {
+	return level >= l.Get()
+}
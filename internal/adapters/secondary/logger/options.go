@@ -11,8 +11,11 @@ import (
 // config holds the configuration for the logger.
 type config struct {
 	level      zerolog.Level
+	dynamic    *Level
 	output     io.Writer
 	timeFormat string
+	console    bool
+	sampling   map[zerolog.Level]uint32
 }
 
 // Option defines a function that configures the logger.
@@ -25,6 +28,14 @@ func WithLevel(level zerolog.Level) Option {
 	}
 }
 
+// WithDynamicLevel makes the logger follow level, which may be changed at
+// runtime. It takes precedence over WithLevel.
+func WithDynamicLevel(level *Level) Option {
+	return func(c *config) {
+		c.dynamic = level
+	}
+}
+
 // WithPrettyConsole sets the output to a human-readable console format.
 func WithPrettyConsole() Option {
 	return func(c *config) {
@@ -41,3 +52,27 @@ func WithOutput(writer io.Writer) Option {
 		c.output = writer
 	}
 }
+
+// WithConsoleFormat renders the logs in a human-readable format instead of
+// JSON, on whatever output is configured.
+func WithConsoleFormat() Option {
+	return func(c *config) {
+		c.console = true
+	}
+}
+
+// WithTimeFormat sets the layout of the timestamp field. Besides Go time
+// layouts it accepts zerolog.TimeFormatUnix and zerolog.TimeFormatUnixMs.
+func WithTimeFormat(layout string) Option {
+	return func(c *config) {
+		c.timeFormat = layout
+	}
+}
+
+// WithSampling keeps only one in n messages of each given level; n of 0 or 1
+// keeps every message.
+func WithSampling(rates map[zerolog.Level]uint32) Option {
+	return func(c *config) {
+		c.sampling = rates
+	}
+}
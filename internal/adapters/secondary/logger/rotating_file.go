@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// RotatingFile is an io.WriteCloser appending to a file that is rotated
+// once it would grow beyond a maximum size. Rotated files are renamed to
+// path.1, path.2, ... with path.1 the most recent; the oldest beyond
+// maxBackups are removed.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewRotatingFile opens path for appending, creating it and its directory
+// when missing. maxSize is in bytes; zero disables rotation.
+func NewRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	f := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var rotateErr error
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		rotateErr = f.rotate()
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	if err == nil {
+		err = rotateErr
+	}
+	return n, err
+}
+
+// Sync flushes the current file to disk.
+func (f *RotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Sync()
+}
+
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+// rotate must be called with f.mu held. The current file is renamed while
+// still open and replaced by a new one. Logging always carries on: in the
+// current file when renaming fails, and in the old handle, wherever it now
+// lives, when the new file cannot be opened. Any failure is reported once
+// the switch is done.
+func (f *RotatingFile) rotate() error {
+	var err error
+	if f.maxBackups <= 0 {
+		err = os.Remove(f.path)
+	} else {
+		_ = os.Remove(f.backup(f.maxBackups))
+		for i := f.maxBackups - 1; i >= 1; i-- {
+			if rerr := os.Rename(f.backup(i), f.backup(i+1)); rerr != nil && !os.IsNotExist(rerr) {
+				err = rerr
+			}
+		}
+		if rerr := os.Rename(f.path, f.backup(1)); rerr != nil {
+			err = rerr
+		}
+	}
+
+	old := f.file
+	if oerr := f.open(); oerr != nil {
+		// Try again once another maxSize bytes went to the old handle.
+		f.size = 0
+		return fmt.Errorf("failed to rotate log file: %w", errors.Join(err, oerr))
+	}
+	if cerr := old.Close(); cerr != nil && err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	return nil
+}
+
+func (f *RotatingFile) backup(n int) string {
+	return fmt.Sprintf("%s.%d", f.path, n)
+}
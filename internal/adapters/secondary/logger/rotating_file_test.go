@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(raw)
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	f, err := NewRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	defer f.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Write(%q): %v", line, err)
+		}
+	}
+
+	// Each line overflows the 10 bytes, so each one starts a new file and
+	// only the two most recent backups are kept.
+	want := map[string]string{path: "fourth\n", path + ".1": "third\n", path + ".2": "second\n"}
+	for name, content := range want {
+		if got := readFile(t, name); got != content {
+			t.Errorf("%s holds %q, want %q", filepath.Base(name), got, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("backup beyond max_backups exists: %v", err)
+	}
+}
+
+func TestRotatingFileAppendsToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("0123456789"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := NewRotatingFile(path, 10, 1)
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write([]byte("next\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got := readFile(t, path+".1"); got != "0123456789" {
+		t.Errorf("app.log.1 holds %q, want the previous content", got)
+	}
+}
+
+func TestRotatingFileKeepsLoggingWhenRotationFails(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	f, err := NewRotatingFile(path, 10, 1)
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.Write([]byte("0123456789")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	// A directory in the way of the backup makes renaming fail.
+	if err := os.MkdirAll(filepath.Join(path+".1", "taken"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	n, err := f.Write([]byte("kept\n"))
+	if err == nil || !strings.Contains(err.Error(), "failed to rotate log file") {
+		t.Errorf("Write returned %v, want the rotation error", err)
+	}
+	if n != len("kept\n") {
+		t.Errorf("Write wrote %d bytes, want every byte despite the failed rotation", n)
+	}
+	if got := readFile(t, path); got != "0123456789kept\n" {
+		t.Errorf("app.log holds %q, want the line appended to it", got)
+	}
+
+	// Without its directory no new file can be opened; the old handle is kept.
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("again\n")); err == nil {
+		t.Error("Write succeeded although the log file cannot be reopened")
+	}
+	// The next attempt waits for another 10 bytes.
+	if n, err := f.Write([]byte("ok\n")); err != nil || n != len("ok\n") {
+		t.Errorf("Write after a failed reopen returned %d, %v, want the old handle to keep working", n, err)
+	}
+}
@@ -9,11 +9,13 @@ import (
 	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
 
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // zerologAdapter implements the ports.Logger interface using zerolog.
 type zerologAdapter struct {
 	logger zerolog.Logger
+	level  *Level
 }
 
 // New creates a new logger instance that implements ports.Logger.
@@ -31,28 +33,94 @@ func New(opts ...Option) ports.Logger {
 		opt(&cfg)
 	}
 
-	logger := zerolog.New(cfg.output).
-		Level(cfg.level).
+	level := cfg.dynamic
+	if level == nil {
+		level = NewLevel(cfg.level)
+	}
+
+	// The time format is global to zerolog; the last logger created wins.
+	zerolog.TimeFieldFormat = cfg.timeFormat
+
+	output := cfg.output
+	if cfg.console {
+		output = zerolog.ConsoleWriter{
+			Out:        output,
+			NoColor:    output != os.Stdout && output != os.Stderr,
+			TimeFormat: cfg.timeFormat,
+		}
+	}
+
+	// Filtering happens in the adapter against the shared level, so the
+	// zerolog logger itself lets everything through.
+	logger := zerolog.New(output).
+		Level(zerolog.TraceLevel).
 		With().
 		Timestamp().
 		Logger()
 
-	return &zerologAdapter{logger: logger}
+	if sampler := levelSampler(cfg.sampling); sampler != nil {
+		logger = logger.Sample(sampler)
+	}
+
+	return &zerologAdapter{logger: logger, level: level}
+}
+
+// levelSampler builds a sampler from per level rates, or returns nil when
+// every message is kept.
+func levelSampler(rates map[zerolog.Level]uint32) zerolog.Sampler {
+	var (
+		sampler zerolog.LevelSampler
+		sampled bool
+	)
+	for level, n := range rates {
+		if n <= 1 {
+			continue
+		}
+		s := &zerolog.BasicSampler{N: n}
+		switch level {
+		case zerolog.TraceLevel:
+			sampler.TraceSampler = s
+		case zerolog.DebugLevel:
+			sampler.DebugSampler = s
+		case zerolog.InfoLevel:
+			sampler.InfoSampler = s
+		case zerolog.WarnLevel:
+			sampler.WarnSampler = s
+		case zerolog.ErrorLevel:
+			sampler.ErrorSampler = s
+		default:
+			continue
+		}
+		sampled = true
+	}
+	if !sampled {
+		return nil
+	}
+	return sampler
 }
 
 func (l *zerologAdapter) Debug(msg string) {
-	l.logger.Debug().Msg(msg)
+	if l.level.enabled(zerolog.DebugLevel) {
+		l.logger.Debug().Msg(msg)
+	}
 }
 
 func (l *zerologAdapter) Info(msg string) {
-	l.logger.Info().Msg(msg)
+	if l.level.enabled(zerolog.InfoLevel) {
+		l.logger.Info().Msg(msg)
+	}
 }
 
 func (l *zerologAdapter) Warn(msg string) {
-	l.logger.Warn().Msg(msg)
+	if l.level.enabled(zerolog.WarnLevel) {
+		l.logger.Warn().Msg(msg)
+	}
 }
 
 func (l *zerologAdapter) Error(msg string, err error) {
+	if !l.level.enabled(zerolog.ErrorLevel) {
+		return
+	}
 	event := l.logger.Error()
 	if err != nil {
 		event = event.Err(err)
@@ -66,7 +134,7 @@ func (l *zerologAdapter) Fatal(msg string, err error) {
 
 func (l *zerologAdapter) With(key string, value any) ports.Logger {
 	newLogger := l.logger.With().Interface(key, value).Logger()
-	return &zerologAdapter{logger: newLogger}
+	return &zerologAdapter{logger: newLogger, level: l.level}
 }
 
 func (l *zerologAdapter) WithFields(fields ...ports.Field) ports.Logger {
@@ -74,22 +142,33 @@ func (l *zerologAdapter) WithFields(fields ...ports.Field) ports.Logger {
 	for _, f := range fields {
 		contextBuilder = contextBuilder.Interface(f.Key, f.Value)
 	}
-	return &zerologAdapter{logger: contextBuilder.Logger()}
+	return &zerologAdapter{logger: contextBuilder.Logger(), level: l.level}
 }
 
 // WithContext extracts values from a context and returns a child logger.
-// This is useful for adding request-specific data like correlation IDs.
+// It adds the correlation ID and, when the context carries a span, the
+// trace and span IDs so log lines can be joined with traces.
 func (l *zerologAdapter) WithContext(ctx context.Context) ports.Logger {
 	if ctx == nil {
 		return l
 	}
 
-	// Example: Extract a correlation ID from context
-	// You would set this in a middleware.
+	builder, changed := l.logger.With(), false
+
+	// The correlation ID is set by the HTTP logging middleware.
 	if correlationID, ok := ctx.Value(constants.ContextCorrelationID).(string); ok && correlationID != "" {
-		newLogger := l.logger.With().Str("request_id", correlationID).Logger()
-		return &zerologAdapter{logger: newLogger}
+		builder, changed = builder.Str("request_id", correlationID), true
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		builder = builder.
+			Str("trace_id", sc.TraceID().String()).
+			Str("span_id", sc.SpanID().String())
+		changed = true
 	}
 
-	return l
+	if !changed {
+		return l
+	}
+	return &zerologAdapter{logger: builder.Logger(), level: l.level}
 }
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestLevel(t *testing.T) {
+	var out bytes.Buffer
+	level := NewLevel(zerolog.WarnLevel)
+	log := New(WithOutput(&out), WithDynamicLevel(level)).With("component", "test")
+
+	log.Info("hidden")
+	log.Warn("shown")
+	if err := level.SetLevel("debug"); err != nil {
+		t.Fatalf("SetLevel: %v", err)
+	}
+	log.Debug("shown after the change")
+
+	if got := strings.Count(out.String(), "\n"); got != 2 || strings.Contains(out.String(), "hidden") {
+		t.Errorf("logged %q, want the warning and the debug message only", out.String())
+	}
+	if err := level.SetLevel("verbose"); err == nil {
+		t.Error("SetLevel of an unknown level succeeded")
+	}
+}
+
+func TestConsoleFormat(t *testing.T) {
+	var out bytes.Buffer
+	New(WithOutput(&out), WithConsoleFormat()).Info("hello")
+
+	if got := out.String(); strings.HasPrefix(got, "{") || !strings.Contains(got, "hello") {
+		t.Errorf("logged %q, want a console line", got)
+	}
+}
+
+func TestSampling(t *testing.T) {
+	var out bytes.Buffer
+	log := New(WithOutput(&out), WithSampling(map[zerolog.Level]uint32{zerolog.InfoLevel: 10}))
+
+	for range 100 {
+		log.Info("sampled")
+		log.Warn("kept")
+	}
+
+	if got := strings.Count(out.String(), `"sampled"`); got != 10 {
+		t.Errorf("logged %d of 100 sampled messages, want 10", got)
+	}
+	if got := strings.Count(out.String(), `"kept"`); got != 100 {
+		t.Errorf("logged %d of 100 unsampled messages, want 100", got)
+	}
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// pgxPoolCollector exposes the statistics of a pgx connection pool, the
+// counterpart of the database/sql collector for RegisterDB.
+type pgxPoolCollector struct {
+	pool *pgxpool.Pool
+
+	maxConns         *prometheus.Desc
+	totalConns       *prometheus.Desc
+	acquiredConns    *prometheus.Desc
+	idleConns        *prometheus.Desc
+	acquires         *prometheus.Desc
+	acquireDuration  *prometheus.Desc
+	emptyAcquires    *prometheus.Desc
+	canceledAcquires *prometheus.Desc
+}
+
+// RegisterPgxPool exposes the statistics of pool as metrics labelled with name.
+func (p *Prometheus) RegisterPgxPool(name string, pool *pgxpool.Pool) error {
+	labels := prometheus.Labels{"db_name": name}
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName("pgxpool", "", metric), help, nil, labels)
+	}
+	return p.registry.Register(&pgxPoolCollector{
+		pool:             pool,
+		maxConns:         desc("max_conns", "Maximum size of the pool."),
+		totalConns:       desc("total_conns", "Connections currently in the pool."),
+		acquiredConns:    desc("acquired_conns", "Connections currently in use."),
+		idleConns:        desc("idle_conns", "Idle connections in the pool."),
+		acquires:         desc("acquires_total", "Successful connection acquisitions."),
+		acquireDuration:  desc("acquire_duration_seconds_total", "Time spent acquiring connections."),
+		emptyAcquires:    desc("empty_acquires_total", "Acquisitions that waited for a connection because the pool was empty."),
+		canceledAcquires: desc("canceled_acquires_total", "Acquisitions cancelled by their context."),
+	})
+}
+
+func (c *pgxPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.maxConns
+	ch <- c.totalConns
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.acquires
+	ch <- c.acquireDuration
+	ch <- c.emptyAcquires
+	ch <- c.canceledAcquires
+}
+
+func (c *pgxPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquires, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquires, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+// Prometheus implements ports.Metrics and ports.HTTPMetrics with a dedicated
+// Prometheus registry.
+type Prometheus struct {
+	registry *prometheus.Registry
+
+	httpRequests      *prometheus.CounterVec
+	httpDuration      *prometheus.HistogramVec
+	httpInFlight      prometheus.Gauge
+	operations        *prometheus.CounterVec
+	operationDuration *prometheus.HistogramVec
+}
+
+var (
+	_ ports.Metrics     = (*Prometheus)(nil)
+	_ ports.HTTPMetrics = (*Prometheus)(nil)
+)
+
+// NewPrometheus creates the collectors under namespace and registers them,
+// together with the Go runtime and process collectors.
+func NewPrometheus(namespace string) *Prometheus {
+	p := &Prometheus{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by method, route template and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		httpInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_in_flight",
+			Help:      "HTTP requests currently being served.",
+		}),
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "service",
+			Name:      "operations_total",
+			Help:      "Service operations by name and outcome.",
+		}, []string{"operation", "outcome"}),
+		operationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "service",
+			Name:      "operation_duration_seconds",
+			Help:      "Service operation latency by name and outcome.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "outcome"}),
+	}
+
+	p.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		p.httpRequests,
+		p.httpDuration,
+		p.httpInFlight,
+		p.operations,
+		p.operationDuration,
+	)
+	return p
+}
+
+// RegisterDB exposes the connection pool statistics of db as gauges labelled
+// with name.
+func (p *Prometheus) RegisterDB(name string, db *sql.DB) error {
+	return p.registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler serves the registry in the Prometheus text exposition format.
+func (p *Prometheus) Handler() http.Handler {
+	return promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{Registry: p.registry})
+}
+
+func (p *Prometheus) RequestStarted() {
+	p.httpInFlight.Inc()
+}
+
+func (p *Prometheus) RequestFinished(method, route string, status int, duration time.Duration) {
+	p.httpInFlight.Dec()
+	code := strconv.Itoa(status)
+	p.httpRequests.WithLabelValues(method, route, code).Inc()
+	p.httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+func (p *Prometheus) ObserveOperation(operation, outcome string, duration time.Duration) {
+	p.operations.WithLabelValues(operation, outcome).Inc()
+	p.operationDuration.WithLabelValues(operation, outcome).Observe(duration.Seconds())
+}
@@ -0,0 +1,88 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sqlite "github.com/glebarez/go-sqlite"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+)
+
+// PostgreSQL error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+	pgQueryCanceled        = "57014"
+)
+
+// SQLite result codes, see https://www.sqlite.org/rescode.html. Extended codes
+// carry the primary code in their low byte.
+const (
+	sqliteBusy                 = 5
+	sqliteLocked               = 6
+	sqliteInterrupt            = 9
+	sqliteConstraintForeignKey = 787
+	sqliteConstraintPrimaryKey = 1555
+	sqliteConstraintUnique     = 2067
+)
+
+// translateError maps GORM, PostgreSQL and SQLite errors to domain errors. The
+// original error stays in the chain for logging.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ErrItemNotFound
+	}
+
+	// A deadline cancels the query; a cancelled context means the caller,
+	// usually a disconnected client, gave up.
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", domain.ErrTimeout, err)
+	}
+	if errors.Is(err, context.Canceled) {
+		return fmt.Errorf("%w: %w", domain.ErrUnavailable, err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return fmt.Errorf("%w: %w", domain.ErrItemExists, err)
+		case pgForeignKeyViolation:
+			return fmt.Errorf("%w: %w", domain.ErrInvalidReference, err)
+		case pgSerializationFailure, pgDeadlockDetected:
+			return fmt.Errorf("%w: %w: %w", domain.ErrUnavailable, domain.ErrTxConflict, err)
+		case pgQueryCanceled:
+			// Raised by statement_timeout.
+			return fmt.Errorf("%w: %w", domain.ErrTimeout, err)
+		}
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code()
+		switch {
+		case code == sqliteConstraintUnique, code == sqliteConstraintPrimaryKey:
+			return fmt.Errorf("%w: %w", domain.ErrItemExists, err)
+		case code == sqliteConstraintForeignKey:
+			return fmt.Errorf("%w: %w", domain.ErrInvalidReference, err)
+		case code&0xff == sqliteBusy, code&0xff == sqliteLocked:
+			// Another connection held the database past busy_timeout.
+			return fmt.Errorf("%w: %w", domain.ErrUnavailable, err)
+		case code&0xff == sqliteInterrupt:
+			// Raised when the context of a running statement is done.
+			return fmt.Errorf("%w: %w", domain.ErrTimeout, err)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,79 @@
+package gorm
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/krisadabig/supreme-ms-item/config"
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+)
+
+func TestTranslateSQLiteErrors(t *testing.T) {
+	db := openSQLite(t)
+	if err := db.Exec(`CREATE TABLE things (id INTEGER PRIMARY KEY, name TEXT UNIQUE, parent INTEGER REFERENCES things (id))`).Error; err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	if err := db.Exec(`INSERT INTO things (id, name) VALUES (1, 'first')`).Error; err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		sql  string
+		want error
+	}{
+		{"primary key", `INSERT INTO things (id, name) VALUES (1, 'second')`, domain.ErrItemExists},
+		{"unique", `INSERT INTO things (id, name) VALUES (2, 'first')`, domain.ErrItemExists},
+		{"foreign key", `INSERT INTO things (id, name, parent) VALUES (3, 'third', 42)`, domain.ErrInvalidReference},
+		{"no row", `SELECT * FROM things WHERE id = 42`, domain.ErrItemNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.want == domain.ErrItemNotFound {
+				var row struct{ ID int64 }
+				err = db.Raw(tt.sql).First(&row).Error
+			} else {
+				err = db.Exec(tt.sql).Error
+			}
+			if got := translateError(err); !errors.Is(got, tt.want) {
+				t.Errorf("translateError(%v) = %v, want %v", err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslateSQLiteBusy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "items.db")
+	open := func(busyTimeout time.Duration) *gorm.DB {
+		cfg := config.SQLiteConfig{Path: path, BusyTimeout: busyTimeout}
+		db, err := gorm.Open(sqlite.Open(cfg.ConnectionString()), &gorm.Config{Logger: gormLogger})
+		if err != nil {
+			t.Fatalf("open SQLite: %v", err)
+		}
+		sqlDB, _ := db.DB()
+		t.Cleanup(func() { sqlDB.Close() })
+		return db
+	}
+	holder, waiter := open(time.Second), open(10*time.Millisecond)
+	if err := holder.Exec(`CREATE TABLE things (id INTEGER PRIMARY KEY)`).Error; err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	// Transactions take the write lock when they begin.
+	tx := holder.Begin()
+	if tx.Error != nil {
+		t.Fatalf("begin: %v", tx.Error)
+	}
+	defer tx.Rollback()
+
+	err := waiter.Exec(`INSERT INTO things (id) VALUES (1)`).Error
+	if got := translateError(err); !errors.Is(got, domain.ErrUnavailable) {
+		t.Errorf("translateError(%v) = %v, want ErrUnavailable", err, got)
+	}
+}
@@ -1,6 +1,12 @@
 package gorm
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
 	"gorm.io/gorm"
 )
@@ -15,35 +21,286 @@ func NewGormItemRepository(db *gorm.DB) *GormItemRepository {
 	}
 }
 
-func (r *GormItemRepository) Create(item *domain.Item) error {
-	return r.db.Create(item).Error
+func (r *GormItemRepository) Create(ctx context.Context, item *domain.Item) error {
+	return translateError(r.conn(ctx).Create(item).Error)
 }
 
-func (r *GormItemRepository) Update(item *domain.Item) error {
-	return r.db.Save(item).Error
+// createBatchSize keeps the bound parameters of a multi-row insert well
+// below the PostgreSQL limit of 65535.
+const createBatchSize = 1000
+
+// CreateMany inserts items with multi-row inserts in a single transaction.
+func (r *GormItemRepository) CreateMany(ctx context.Context, items []*domain.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return translateError(r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(items, createBatchSize).Error
+	}))
 }
 
-func (r *GormItemRepository) Delete(item *domain.Item) error {
-	return r.db.Delete(item).Error
+// updatableColumns maps the mutable item fields to their columns and values.
+var updatableColumns = map[string]struct {
+	column string
+	value  func(*domain.Item) any
+}{
+	domain.ItemFieldTitle:       {"title", func(i *domain.Item) any { return i.Title }},
+	domain.ItemFieldDescription: {"description", func(i *domain.Item) any { return i.Description }},
 }
 
-func (r *GormItemRepository) GetAll() ([]domain.Item, error) {
-	var items []domain.Item
-	err := r.db.Find(&items).Error
-	return items, err
+// Update writes the given fields of item, provided it belongs to item.UserID
+// and is still at item.Version, and reloads it so the version and timestamps
+// reflect the stored row.
+func (r *GormItemRepository) Update(ctx context.Context, item *domain.Item, fields []string) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("%w: no fields to update", domain.ErrInvalidItem)
+	}
+
+	updates := map[string]any{
+		"version":    gorm.Expr("version + 1"),
+		"updated_at": time.Now().UTC(),
+	}
+	for _, field := range fields {
+		col, ok := updatableColumns[field]
+		if !ok {
+			return fmt.Errorf("%w: field %q cannot be updated", domain.ErrInvalidItem, field)
+		}
+		updates[col.column] = col.value(item)
+	}
+
+	db := r.conn(ctx)
+	result := db.Model(&domain.Item{}).
+		Scopes(notDeleted).
+		Where("id = ? AND user_id = ? AND version = ?", item.ID, item.UserID, item.Version).
+		Updates(updates)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return r.missError(db.Scopes(notDeleted), item.UserID, item.ID)
+	}
+	return translateError(db.Where("id = ? AND user_id = ?", item.ID, item.UserID).First(item).Error)
+}
+
+// UpdateMany updates items one by one in a single transaction.
+func (r *GormItemRepository) UpdateMany(ctx context.Context, items []*domain.Item, fields []string) error {
+	return r.batch(ctx, len(items), func(ctx context.Context, i int) error {
+		return r.Update(ctx, items[i], fields)
+	})
+}
+
+// Delete moves the item to the trash by setting its deletion timestamp. A
+// non-zero expectedVersion must match the stored version.
+func (r *GormItemRepository) Delete(ctx context.Context, userID string, id int64, expectedVersion int64) error {
+	db := r.conn(ctx).Model(&domain.Item{}).
+		Scopes(notDeleted).
+		Where("id = ? AND user_id = ?", id, userID)
+	if expectedVersion != 0 {
+		db = db.Where("version = ?", expectedVersion)
+	}
+
+	result := db.UpdateColumns(map[string]any{
+		"deleted_at": time.Now().UTC(),
+		"version":    gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return r.missError(r.conn(ctx).Scopes(notDeleted), userID, id)
+	}
+	return nil
+}
+
+// DeleteMany trashes the referenced items one by one in a single transaction.
+func (r *GormItemRepository) DeleteMany(ctx context.Context, userID string, refs []domain.ItemRef) error {
+	return r.batch(ctx, len(refs), func(ctx context.Context, i int) error {
+		return r.Delete(ctx, userID, refs[i].ID, refs[i].Version)
+	})
+}
+
+// batch runs write for each of n items with a context carrying one
+// transaction, which is rolled back when an item fails. Within a transaction
+// of the TxManager it is a savepoint.
+func (r *GormItemRepository) batch(ctx context.Context, n int, write func(ctx context.Context, i int) error) error {
+	if n == 0 {
+		return nil
+	}
+	err := r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		ctx := context.WithValue(ctx, txKey{}, tx)
+		for i := range n {
+			if err := write(ctx, i); err != nil {
+				return &domain.BatchItemError{Index: i, Err: err}
+			}
+		}
+		return nil
+	})
+	var itemErr *domain.BatchItemError
+	if errors.As(err, &itemErr) {
+		return err
+	}
+	return translateError(err)
+}
+
+// Restore takes a trashed item out of the trash.
+func (r *GormItemRepository) Restore(ctx context.Context, userID string, id int64) (*domain.Item, error) {
+	result := r.conn(ctx).Model(&domain.Item{}).
+		Scopes(deleted).
+		Where("id = ? AND user_id = ?", id, userID).
+		UpdateColumns(map[string]any{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return nil, translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.ErrItemNotFound
+	}
+	return r.GetByID(ctx, userID, id)
+}
+
+// missError explains why a conditional write matched no row: the item either
+// does not exist for the user or is at another version.
+func (r *GormItemRepository) missError(db *gorm.DB, userID string, id int64) error {
+	var count int64
+	err := db.Model(&domain.Item{}).Where("id = ? AND user_id = ?", id, userID).Count(&count).Error
+	if err != nil {
+		return translateError(err)
+	}
+	if count == 0 {
+		return domain.ErrItemNotFound
+	}
+	return domain.ErrVersionMismatch
+}
+
+// Purge permanently removes items trashed before the given time.
+func (r *GormItemRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	result := r.conn(ctx).Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore.UTC()).Delete(&domain.Item{})
+	return result.RowsAffected, translateError(result.Error)
 }
 
-func (r *GormItemRepository) GetByID(id int64) (*domain.Item, error) {
+func (r *GormItemRepository) GetAll(ctx context.Context, query domain.ItemQuery) (*domain.ItemPage, error) {
+	return r.list(r.conn(ctx).Scopes(notDeleted), query)
+}
+
+func (r *GormItemRepository) GetByID(ctx context.Context, userID string, id int64) (*domain.Item, error) {
 	var item domain.Item
-	err := r.db.First(&item, id).Error
+	err := r.conn(ctx).Scopes(notDeleted).Where("id = ?", id).First(&item).Error
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
+	}
+	if item.UserID != userID {
+		return nil, domain.ErrItemNotOwned
 	}
 	return &item, nil
 }
 
-func (r *GormItemRepository) GetByUserID(userID string) ([]domain.Item, error) {
+func (r *GormItemRepository) GetByUserID(ctx context.Context, userID string, query domain.ItemQuery) (*domain.ItemPage, error) {
+	query.UserID = userID
+	return r.list(r.conn(ctx).Scopes(notDeleted), query)
+}
+
+// GetDeleted lists trashed items.
+func (r *GormItemRepository) GetDeleted(ctx context.Context, query domain.ItemQuery) (*domain.ItemPage, error) {
+	return r.list(r.conn(ctx).Scopes(deleted), query)
+}
+
+// notDeleted restricts a query to items that are not in the trash.
+func notDeleted(db *gorm.DB) *gorm.DB {
+	return db.Where("deleted_at IS NULL")
+}
+
+// deleted restricts a query to items in the trash.
+func deleted(db *gorm.DB) *gorm.DB {
+	return db.Where("deleted_at IS NOT NULL")
+}
+
+// list runs a keyset-paginated query. One extra row is fetched to find out
+// whether another page follows.
+func (r *GormItemRepository) list(db *gorm.DB, query domain.ItemQuery) (*domain.ItemPage, error) {
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+
+	db = applyFilter(db, query.ItemFilter)
+
+	column := string(query.SortBy)
+	cmp, dir := ">", "ASC"
+	if query.SortDir == domain.SortDesc {
+		cmp, dir = "<", "DESC"
+	}
+
+	if query.Cursor != "" {
+		cursor, err := domain.DecodeCursor(query.Cursor, query.SortBy, query.SortDir)
+		if err != nil {
+			return nil, err
+		}
+		var value any
+		switch query.SortBy {
+		case domain.SortByCreatedAt, domain.SortByUpdatedAt:
+			value = cursor.Time.UTC()
+		case domain.SortByTitle:
+			value = *cursor.Title
+		}
+		if value == nil {
+			db = db.Where(fmt.Sprintf("id %s ?", cmp), cursor.ID)
+		} else {
+			db = db.Where(
+				fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", column, cmp),
+				value, value, cursor.ID,
+			)
+		}
+	}
+
+	if query.SortBy != domain.SortByID {
+		db = db.Order(column + " " + dir)
+	}
+	db = db.Order("id " + dir)
+
 	var items []domain.Item
-	err := r.db.Where("user_id = ?", userID).Find(&items).Error
-	return items, err
+	if err := db.Limit(query.Limit + 1).Find(&items).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	page := &domain.ItemPage{Items: items}
+	if len(items) > query.Limit {
+		page.Items = items[:query.Limit]
+		page.HasMore = true
+		last := page.Items[len(page.Items)-1]
+		page.NextCursor = domain.NewCursor(&last, query.SortBy, query.SortDir).Encode()
+	}
+	if page.Items == nil {
+		page.Items = []domain.Item{}
+	}
+	return page, nil
+}
+
+// applyFilter narrows db down to filter. Times are compared in UTC, since
+// SQLite stores them as text that sorts chronologically only within a zone.
+func applyFilter(db *gorm.DB, filter domain.ItemFilter) *gorm.DB {
+	if filter.UserID != "" {
+		db = db.Where("user_id = ?", filter.UserID)
+	}
+	if filter.TitlePrefix != "" {
+		db = db.Where(`title LIKE ? ESCAPE '\'`, escapeLike(filter.TitlePrefix)+"%")
+	}
+	if filter.CreatedAfter != nil {
+		db = db.Where("created_at >= ?", filter.CreatedAfter.UTC())
+	}
+	if filter.CreatedBefore != nil {
+		db = db.Where("created_at < ?", filter.CreatedBefore.UTC())
+	}
+	if filter.UpdatedAfter != nil {
+		db = db.Where("updated_at >= ?", filter.UpdatedAfter.UTC())
+	}
+	if filter.UpdatedBefore != nil {
+		db = db.Where("updated_at < ?", filter.UpdatedBefore.UTC())
+	}
+	return db
+}
+
+// escapeLike escapes the LIKE wildcards so a prefix is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
 }
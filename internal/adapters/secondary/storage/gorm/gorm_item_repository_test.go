@@ -0,0 +1,117 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/krisadabig/supreme-ms-item/config"
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/logger"
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/storage/migrations"
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/storage/storagetest"
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+// openSQLite returns a migrated SQLite database in a temporary file,
+// connected the way the sqlite driver connects.
+func openSQLite(tb testing.TB) *gorm.DB {
+	tb.Helper()
+	cfg := config.SQLiteConfig{Path: filepath.Join(tb.TempDir(), "items.db"), BusyTimeout: 5 * time.Second}
+	db, err := gorm.Open(sqlite.Open(cfg.ConnectionString()), &gorm.Config{
+		NowFunc: func() time.Time { return time.Now().UTC() },
+		Logger:  gormLogger,
+	})
+	if err != nil {
+		tb.Fatalf("open SQLite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		tb.Fatalf("open SQLite: %v", err)
+	}
+	tb.Cleanup(func() { sqlDB.Close() })
+
+	migrator, err := migrations.New(sqlDB, migrations.SQLite, logger.New(logger.WithOutput(io.Discard)))
+	if err != nil {
+		tb.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		tb.Fatalf("apply migrations: %v", err)
+	}
+	return db
+}
+
+// openPostgres returns a database in a fresh schema of storagetest.PostgresEnv,
+// connected the way the postgres driver connects, or skips tb.
+func openPostgres(tb testing.TB) *gorm.DB {
+	tb.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  storagetest.PostgresDSN(tb),
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{Logger: gormLogger})
+	if err != nil {
+		tb.Fatalf("open PostgreSQL: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		tb.Fatalf("open PostgreSQL: %v", err)
+	}
+	tb.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+// gormLogger keeps the expected failures of the contract out of the output.
+var gormLogger = gormlogger.Discard
+
+func TestSQLiteContract(t *testing.T) {
+	storagetest.RunItemRepositoryContract(t, func(t *testing.T) ports.ItemRepository {
+		return NewGormItemRepository(openSQLite(t))
+	})
+}
+
+func TestPostgresContract(t *testing.T) {
+	storagetest.RunItemRepositoryContract(t, func(t *testing.T) ports.ItemRepository {
+		return NewGormItemRepository(openPostgres(t))
+	})
+}
+
+func TestSQLiteTxManagerContract(t *testing.T) {
+	storagetest.RunTxManagerContract(t, func(t *testing.T) (ports.ItemRepository, ports.TxManager) {
+		db := openSQLite(t)
+		return NewGormItemRepository(db), NewTxManager(db, sql.LevelDefault)
+	})
+}
+
+func TestPostgresTxManagerContract(t *testing.T) {
+	storagetest.RunTxManagerContract(t, func(t *testing.T) (ports.ItemRepository, ports.TxManager) {
+		db := openPostgres(t)
+		return NewGormItemRepository(db), NewTxManager(db, sql.LevelSerializable)
+	})
+	storagetest.RunTxConflictContract(t, func(t *testing.T) (ports.ItemRepository, ports.TxManager) {
+		db := openPostgres(t)
+		return NewGormItemRepository(db), NewTxManager(db, sql.LevelRepeatableRead)
+	})
+}
+
+func BenchmarkSQLite(b *testing.B) {
+	storagetest.RunItemRepositoryBenchmarks(b, func(b *testing.B) ports.ItemRepository {
+		return NewGormItemRepository(openSQLite(b))
+	})
+}
+
+func BenchmarkPostgres(b *testing.B) {
+	db := openPostgres(b)
+	storagetest.RunItemRepositoryBenchmarks(b, func(b *testing.B) ports.ItemRepository {
+		if err := db.Exec("TRUNCATE items RESTART IDENTITY").Error; err != nil {
+			b.Fatalf("empty items: %v", err)
+		}
+		return NewGormItemRepository(db)
+	})
+}
@@ -0,0 +1,31 @@
+package gorm
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+// databaseChecker implements ports.HealthChecker by pinging the connection pool.
+type databaseChecker struct {
+	db *gorm.DB
+}
+
+// NewDatabaseChecker returns a health checker for the database behind db.
+func NewDatabaseChecker(db *gorm.DB) ports.HealthChecker {
+	return &databaseChecker{db: db}
+}
+
+func (c *databaseChecker) Name() string {
+	return "database"
+}
+
+func (c *databaseChecker) Check(ctx context.Context) error {
+	sqlDB, err := c.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
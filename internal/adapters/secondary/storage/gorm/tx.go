@@ -0,0 +1,57 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+)
+
+// txKey is the context key of the transaction a GormItemRepository uses.
+type txKey struct{}
+
+// TxManager runs functions in transactions of a GORM database. Repositories
+// on the same database use the transaction of the context they are called
+// with.
+type TxManager struct {
+	db   *gorm.DB
+	opts *sql.TxOptions
+}
+
+// NewTxManager returns a TxManager starting transactions on db at isolation;
+// sql.LevelDefault keeps the level of the database, the only choice SQLite
+// accepts.
+func NewTxManager(db *gorm.DB, isolation sql.IsolationLevel) *TxManager {
+	m := &TxManager{db: db}
+	if isolation != sql.LevelDefault {
+		m.opts = &sql.TxOptions{Isolation: isolation}
+	}
+	return m
+}
+
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	// Errors of fn are already translated by the repository; only those of
+	// beginning and committing the transaction are left.
+	var fnErr error
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		fnErr = fn(context.WithValue(ctx, txKey{}, tx))
+		return fnErr
+	}, m.opts)
+	if fnErr != nil {
+		return fnErr
+	}
+	return translateError(err)
+}
+
+// conn returns the transaction ctx carries, or the database of r outside of
+// one, bound to ctx.
+func (r *GormItemRepository) conn(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
+}
@@ -0,0 +1,489 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+)
+
+// MemoryItemRepository keeps items in process memory. It follows the
+// semantics of the SQL repositories and suits tests and local development;
+// nothing survives a restart.
+type MemoryItemRepository struct {
+	mu     sync.RWMutex
+	items  map[int64]*domain.Item
+	nextID int64
+}
+
+func NewMemoryItemRepository() *MemoryItemRepository {
+	return &MemoryItemRepository{
+		items:  make(map[int64]*domain.Item),
+		nextID: 1,
+	}
+}
+
+// now returns the current time at the microsecond precision of PostgreSQL
+// timestamps, so stored and cursor times compare the same in every adapter.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+func (r *MemoryItemRepository) Create(ctx context.Context, item *domain.Item) error {
+	if err := contextError(ctx); err != nil {
+		return err
+	}
+
+	defer r.lock(ctx)()
+
+	if _, ok := r.items[item.ID]; ok {
+		return domain.ErrItemExists
+	}
+	r.insert(ctx, item, now())
+	return nil
+}
+
+// CreateMany stores items unless one of them reuses a taken ID.
+func (r *MemoryItemRepository) CreateMany(ctx context.Context, items []*domain.Item) error {
+	if err := contextError(ctx); err != nil {
+		return err
+	}
+
+	defer r.lock(ctx)()
+
+	ids := make(map[int64]bool, len(items))
+	for _, item := range items {
+		if item.ID == 0 {
+			continue
+		}
+		if _, ok := r.items[item.ID]; ok || ids[item.ID] {
+			return domain.ErrItemExists
+		}
+		ids[item.ID] = true
+		// Keep the IDs assigned below clear of the given ones.
+		r.nextID = max(r.nextID, item.ID+1)
+	}
+
+	ts := now()
+	for _, item := range items {
+		r.insert(ctx, item, ts)
+	}
+	return nil
+}
+
+// insert assigns an ID unless item has one, fills in the defaults the SQL
+// schema would, and stores a copy of item. It must be called with r.mu held.
+func (r *MemoryItemRepository) insert(ctx context.Context, item *domain.Item, ts time.Time) {
+	if item.ID == 0 {
+		item.ID = r.nextID
+	}
+	r.nextID = max(r.nextID, item.ID+1)
+	r.remember(ctx, item.ID)
+
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = ts
+	}
+	if item.UpdatedAt.IsZero() {
+		item.UpdatedAt = ts
+	}
+	if item.Version == 0 {
+		item.Version = 1
+	}
+
+	r.items[item.ID] = clone(item)
+}
+
+// Update writes the given fields of item, provided it belongs to item.UserID
+// and is still at item.Version, and reloads it so the version and timestamps
+// reflect the stored item.
+func (r *MemoryItemRepository) Update(ctx context.Context, item *domain.Item, fields []string) error {
+	if err := checkFields(fields); err != nil {
+		return err
+	}
+	if err := contextError(ctx); err != nil {
+		return err
+	}
+
+	defer r.lock(ctx)()
+
+	return r.update(ctx, item, fields, now())
+}
+
+// UpdateMany updates items in order and restores the touched items when one
+// of them fails.
+func (r *MemoryItemRepository) UpdateMany(ctx context.Context, items []*domain.Item, fields []string) error {
+	if len(items) == 0 {
+		return nil
+	}
+	if err := checkFields(fields); err != nil {
+		return err
+	}
+	if err := contextError(ctx); err != nil {
+		return err
+	}
+
+	defer r.lock(ctx)()
+
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	ts := now()
+	return r.batch(ids, func(i int) error {
+		return r.update(ctx, items[i], fields, ts)
+	})
+}
+
+// checkFields rejects updates without fields or of fields clients may not
+// change.
+func checkFields(fields []string) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("%w: no fields to update", domain.ErrInvalidItem)
+	}
+	for _, field := range fields {
+		if !slices.Contains(domain.MutableItemFields, field) {
+			return fmt.Errorf("%w: field %q cannot be updated", domain.ErrInvalidItem, field)
+		}
+	}
+	return nil
+}
+
+// update implements Update. It must be called with r.mu held.
+func (r *MemoryItemRepository) update(ctx context.Context, item *domain.Item, fields []string, ts time.Time) error {
+	stored, err := r.active(item.UserID, item.ID)
+	if err != nil {
+		return err
+	}
+	if stored.Version != item.Version {
+		return domain.ErrVersionMismatch
+	}
+	r.remember(ctx, item.ID)
+
+	for _, field := range fields {
+		switch field {
+		case domain.ItemFieldTitle:
+			stored.Title = cloneString(item.Title)
+		case domain.ItemFieldDescription:
+			stored.Description = cloneString(item.Description)
+		}
+	}
+	stored.Version++
+	stored.UpdatedAt = ts
+
+	*item = *clone(stored)
+	return nil
+}
+
+// Delete moves the item to the trash by setting its deletion timestamp. A
+// non-zero expectedVersion must match the stored version.
+func (r *MemoryItemRepository) Delete(ctx context.Context, userID string, id int64, expectedVersion int64) error {
+	if err := contextError(ctx); err != nil {
+		return err
+	}
+
+	defer r.lock(ctx)()
+
+	return r.trash(ctx, userID, id, expectedVersion, now())
+}
+
+// DeleteMany trashes the referenced items in order and restores them when
+// one of them fails.
+func (r *MemoryItemRepository) DeleteMany(ctx context.Context, userID string, refs []domain.ItemRef) error {
+	if len(refs) == 0 {
+		return nil
+	}
+	if err := contextError(ctx); err != nil {
+		return err
+	}
+
+	defer r.lock(ctx)()
+
+	ids := make([]int64, len(refs))
+	for i, ref := range refs {
+		ids[i] = ref.ID
+	}
+	ts := now()
+	return r.batch(ids, func(i int) error {
+		return r.trash(ctx, userID, refs[i].ID, refs[i].Version, ts)
+	})
+}
+
+// trash implements Delete. It must be called with r.mu held.
+func (r *MemoryItemRepository) trash(ctx context.Context, userID string, id, expectedVersion int64, ts time.Time) error {
+	stored, err := r.active(userID, id)
+	if err != nil {
+		return err
+	}
+	if expectedVersion != 0 && stored.Version != expectedVersion {
+		return domain.ErrVersionMismatch
+	}
+	r.remember(ctx, id)
+
+	deletedAt := ts
+	stored.DeletedAt = &deletedAt
+	stored.Version++
+	return nil
+}
+
+// batch runs write for each item of a batch, given their IDs, like a
+// transaction: when an item fails, the items written before it are restored.
+// It must be called with r.mu held.
+func (r *MemoryItemRepository) batch(ids []int64, write func(i int) error) error {
+	saved := make(map[int64]domain.Item, len(ids))
+	for i, id := range ids {
+		if stored, ok := r.items[id]; ok {
+			if _, done := saved[id]; !done {
+				saved[id] = *clone(stored)
+			}
+		}
+		if err := write(i); err != nil {
+			for id, item := range saved {
+				*r.items[id] = item
+			}
+			return &domain.BatchItemError{Index: i, Err: err}
+		}
+	}
+	return nil
+}
+
+// Restore takes a trashed item out of the trash.
+func (r *MemoryItemRepository) Restore(ctx context.Context, userID string, id int64) (*domain.Item, error) {
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+
+	defer r.lock(ctx)()
+
+	stored, ok := r.items[id]
+	if !ok || stored.UserID != userID || !stored.IsDeleted() {
+		return nil, domain.ErrItemNotFound
+	}
+	r.remember(ctx, id)
+	stored.DeletedAt = nil
+	stored.Version++
+	return clone(stored), nil
+}
+
+// Purge permanently removes items trashed before the given time.
+func (r *MemoryItemRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	if err := contextError(ctx); err != nil {
+		return 0, err
+	}
+
+	defer r.lock(ctx)()
+
+	var purged int64
+	for id, item := range r.items {
+		if item.IsDeleted() && item.DeletedAt.Before(deletedBefore) {
+			r.remember(ctx, id)
+			delete(r.items, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (r *MemoryItemRepository) GetAll(ctx context.Context, query domain.ItemQuery) (*domain.ItemPage, error) {
+	return r.list(ctx, false, query)
+}
+
+func (r *MemoryItemRepository) GetByID(ctx context.Context, userID string, id int64) (*domain.Item, error) {
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+
+	defer r.rlock(ctx)()
+
+	stored, ok := r.items[id]
+	if !ok || stored.IsDeleted() {
+		return nil, domain.ErrItemNotFound
+	}
+	if stored.UserID != userID {
+		return nil, domain.ErrItemNotOwned
+	}
+	return clone(stored), nil
+}
+
+func (r *MemoryItemRepository) GetByUserID(ctx context.Context, userID string, query domain.ItemQuery) (*domain.ItemPage, error) {
+	query.UserID = userID
+	return r.list(ctx, false, query)
+}
+
+// GetDeleted lists trashed items.
+func (r *MemoryItemRepository) GetDeleted(ctx context.Context, query domain.ItemQuery) (*domain.ItemPage, error) {
+	return r.list(ctx, true, query)
+}
+
+// active returns the stored item with id owned by userID unless it is in
+// the trash. It must be called with r.mu held.
+func (r *MemoryItemRepository) active(userID string, id int64) (*domain.Item, error) {
+	stored, ok := r.items[id]
+	if !ok || stored.UserID != userID || stored.IsDeleted() {
+		return nil, domain.ErrItemNotFound
+	}
+	return stored, nil
+}
+
+// list returns one page of the items in or out of the trash, ordered and
+// resumed after the cursor like the keyset pagination of the SQL adapters.
+func (r *MemoryItemRepository) list(ctx context.Context, trashed bool, query domain.ItemQuery) (*domain.ItemPage, error) {
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+	var cursor *domain.Cursor
+	if query.Cursor != "" {
+		c, err := domain.DecodeCursor(query.Cursor, query.SortBy, query.SortDir)
+		if err != nil {
+			return nil, err
+		}
+		cursor = c
+	}
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+
+	unlock := r.rlock(ctx)
+	items := make([]domain.Item, 0, len(r.items))
+	for _, item := range r.items {
+		if item.IsDeleted() == trashed && matches(item, query.ItemFilter) {
+			items = append(items, *clone(item))
+		}
+	}
+	unlock()
+
+	order := func(a, b domain.Item) int {
+		c := compare(&a, sortKey(&b, query.SortBy), b.ID, query.SortBy)
+		if query.SortDir == domain.SortDesc {
+			return -c
+		}
+		return c
+	}
+	slices.SortFunc(items, order)
+
+	if cursor != nil {
+		key := cursorKey(cursor)
+		start, _ := slices.BinarySearchFunc(items, cursor, func(item domain.Item, _ *domain.Cursor) int {
+			c := compare(&item, key, cursor.ID, query.SortBy)
+			if query.SortDir == domain.SortDesc {
+				c = -c
+			}
+			// Items equal to the cursor belong to the previous page.
+			if c == 0 {
+				return -1
+			}
+			return c
+		})
+		items = items[start:]
+	}
+
+	page := &domain.ItemPage{Items: items}
+	if len(items) > query.Limit {
+		page.Items = items[:query.Limit]
+		page.HasMore = true
+		last := page.Items[len(page.Items)-1]
+		page.NextCursor = domain.NewCursor(&last, query.SortBy, query.SortDir).Encode()
+	}
+	return page, nil
+}
+
+// sortKey returns the value item is ordered by, besides its ID.
+func sortKey(item *domain.Item, sortBy domain.SortField) any {
+	switch sortBy {
+	case domain.SortByCreatedAt:
+		return item.CreatedAt
+	case domain.SortByUpdatedAt:
+		return item.UpdatedAt
+	case domain.SortByTitle:
+		if item.Title == nil {
+			return ""
+		}
+		return *item.Title
+	default:
+		return nil
+	}
+}
+
+// cursorKey returns the sort key recorded in a cursor.
+func cursorKey(c *domain.Cursor) any {
+	switch {
+	case c.Time != nil:
+		return *c.Time
+	case c.Title != nil:
+		return *c.Title
+	default:
+		return nil
+	}
+}
+
+// compare orders item against the position (key, id) in ascending order.
+func compare(item *domain.Item, key any, id int64, sortBy domain.SortField) int {
+	var c int
+	switch k := key.(type) {
+	case time.Time:
+		c = sortKey(item, sortBy).(time.Time).Compare(k)
+	case string:
+		c = strings.Compare(sortKey(item, sortBy).(string), k)
+	}
+	if c != 0 {
+		return c
+	}
+	return cmp.Compare(item.ID, id)
+}
+
+// matches reports whether item passes filter.
+func matches(item *domain.Item, filter domain.ItemFilter) bool {
+	switch {
+	case filter.UserID != "" && item.UserID != filter.UserID:
+		return false
+	case filter.TitlePrefix != "" && (item.Title == nil || !strings.HasPrefix(*item.Title, filter.TitlePrefix)):
+		return false
+	case filter.CreatedAfter != nil && item.CreatedAt.Before(*filter.CreatedAfter):
+		return false
+	case filter.CreatedBefore != nil && !item.CreatedAt.Before(*filter.CreatedBefore):
+		return false
+	case filter.UpdatedAfter != nil && item.UpdatedAt.Before(*filter.UpdatedAfter):
+		return false
+	case filter.UpdatedBefore != nil && !item.UpdatedAt.Before(*filter.UpdatedBefore):
+		return false
+	}
+	return true
+}
+
+// contextError reports a done context the way the SQL adapters translate it.
+func contextError(ctx context.Context) error {
+	err := ctx.Err()
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("%w: %w", domain.ErrTimeout, err)
+	default:
+		return fmt.Errorf("%w: %w", domain.ErrUnavailable, err)
+	}
+}
+
+// clone copies item so callers never share memory with the store.
+func clone(item *domain.Item) *domain.Item {
+	c := *item
+	c.Title = cloneString(item.Title)
+	c.Description = cloneString(item.Description)
+	if item.DeletedAt != nil {
+		deletedAt := *item.DeletedAt
+		c.DeletedAt = &deletedAt
+	}
+	return &c
+}
+
+func cloneString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
@@ -0,0 +1,27 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/storage/storagetest"
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+func TestContract(t *testing.T) {
+	storagetest.RunItemRepositoryContract(t, func(t *testing.T) ports.ItemRepository {
+		return NewMemoryItemRepository()
+	})
+}
+
+func TestTxManagerContract(t *testing.T) {
+	storagetest.RunTxManagerContract(t, func(t *testing.T) (ports.ItemRepository, ports.TxManager) {
+		repo := NewMemoryItemRepository()
+		return repo, NewTxManager(repo)
+	})
+}
+
+func BenchmarkRepository(b *testing.B) {
+	storagetest.RunItemRepositoryBenchmarks(b, func(b *testing.B) ports.ItemRepository {
+		return NewMemoryItemRepository()
+	})
+}
@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+)
+
+// txKey is the context key of the transaction of a MemoryItemRepository.
+type txKey struct{}
+
+// memTx is a transaction of a MemoryItemRepository. It holds the write lock
+// of the repository from start to end, so transactions are serializable, and
+// remembers the items it changed to put them back on rollback.
+type memTx struct {
+	repo *MemoryItemRepository
+	// saved holds the state of every changed item before the transaction,
+	// nil for the items it created.
+	saved map[int64]*domain.Item
+}
+
+// TxManager runs functions in transactions of a MemoryItemRepository. The
+// repository methods must be called with the context passed to fn: called
+// with another one, they wait for the transaction to end.
+type TxManager struct {
+	repo *MemoryItemRepository
+}
+
+func NewTxManager(repo *MemoryItemRepository) *TxManager {
+	return &TxManager{repo: repo}
+}
+
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if m.repo.txOf(ctx) != nil {
+		return fn(ctx)
+	}
+	if err := contextError(ctx); err != nil {
+		return err
+	}
+
+	m.repo.mu.Lock()
+	defer m.repo.mu.Unlock()
+
+	tx := &memTx{repo: m.repo, saved: make(map[int64]*domain.Item)}
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		for id, item := range tx.saved {
+			if item == nil {
+				delete(m.repo.items, id)
+				continue
+			}
+			m.repo.items[id] = item
+		}
+		return err
+	}
+	return nil
+}
+
+// txOf returns the transaction of r that ctx carries, if any.
+func (r *MemoryItemRepository) txOf(ctx context.Context) *memTx {
+	if tx, ok := ctx.Value(txKey{}).(*memTx); ok && tx.repo == r {
+		return tx
+	}
+	return nil
+}
+
+// lock takes the write lock of r, unless ctx carries a transaction of r
+// which holds it already, and returns its release.
+func (r *MemoryItemRepository) lock(ctx context.Context) (unlock func()) {
+	if r.txOf(ctx) != nil {
+		return func() {}
+	}
+	r.mu.Lock()
+	return r.mu.Unlock
+}
+
+// rlock is lock for reading.
+func (r *MemoryItemRepository) rlock(ctx context.Context) (unlock func()) {
+	if r.txOf(ctx) != nil {
+		return func() {}
+	}
+	r.mu.RLock()
+	return r.mu.RUnlock
+}
+
+// remember records the state of the item with id before the transaction ctx
+// carries changes it for the first time. It must be called with r.mu held,
+// before the change.
+func (r *MemoryItemRepository) remember(ctx context.Context, id int64) {
+	tx := r.txOf(ctx)
+	if tx == nil {
+		return
+	}
+	if _, ok := tx.saved[id]; ok {
+		return
+	}
+	var saved *domain.Item
+	if stored, ok := r.items[id]; ok {
+		saved = clone(stored)
+	}
+	tx.saved[id] = saved
+}
@@ -0,0 +1,64 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Dialect holds the database specific parts of the migrator. Name is also
+// the directory of the dialect's migration files.
+type Dialect struct {
+	Name        string
+	CreateTable string
+	Insert      string
+	Delete      string
+	// Lock acquires an exclusive, session level migration lock on conn and
+	// returns the function releasing it.
+	Lock func(ctx context.Context, conn *sql.Conn) (unlock func() error, err error)
+}
+
+// advisoryLockKey identifies the migration lock among PostgreSQL advisory locks.
+const advisoryLockKey = 7_241_001_203
+
+// Postgres is the PostgreSQL dialect. The lock is a session advisory lock, so
+// it is released even if the process dies mid-migration.
+var Postgres = Dialect{
+	Name: "postgres",
+	CreateTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    BIGINT      PRIMARY KEY,
+		name       TEXT        NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	Insert: "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)",
+	Delete: "DELETE FROM schema_migrations WHERE version = $1",
+	Lock: func(ctx context.Context, conn *sql.Conn) (func() error, error) {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", advisoryLockKey); err != nil {
+			return nil, err
+		}
+		return func() error {
+			_, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", advisoryLockKey)
+			return err
+		}, nil
+	},
+}
+
+// SQLite is the SQLite dialect. It takes no lock: SQLite admits a single
+// writer at a time and its database file belongs to one node, and each
+// migration runs in its own transaction.
+var SQLite = Dialect{
+	Name: "sqlite",
+	CreateTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER   PRIMARY KEY,
+		name       TEXT      NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`,
+	Insert: "INSERT INTO schema_migrations (version, name) VALUES (?, ?)",
+	Delete: "DELETE FROM schema_migrations WHERE version = ?",
+	Lock: func(context.Context, *sql.Conn) (func() error, error) {
+		return func() error { return nil }, nil
+	},
+}
+
+// Dialects lists every supported dialect. Each keeps its own copy of every
+// migration under the same version and name.
+var Dialects = []Dialect{Postgres, SQLite}
@@ -0,0 +1,318 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+//go:embed postgres/*.sql sqlite/*.sql
+var embedded embed.FS
+
+// SourceDir is the directory, relative to the module root, holding the SQL
+// files embedded into the binary. New migrations are created there.
+const SourceDir = "internal/adapters/secondary/storage/migrations"
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration is a versioned pair of up and down SQL scripts.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status describes whether a migration has been applied.
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrator applies the embedded migrations of a dialect to a database.
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	migrations []Migration
+	logger     ports.Logger
+}
+
+// New loads the embedded migrations of dialect.
+func New(db *sql.DB, dialect Dialect, logger ports.Logger) (*Migrator, error) {
+	sub, err := fs.Sub(embedded, dialect.Name)
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := load(sub)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		db:         db,
+		dialect:    dialect,
+		migrations: migrations,
+		logger:     logger.With("component", "migrator").With("dialect", dialect.Name),
+	}, nil
+}
+
+// Up applies every pending migration in order and returns those applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		done, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if _, ok := done[mig.Version]; ok {
+				continue
+			}
+			m.logger.With("version", mig.Version).With("name", mig.Name).Info("applying migration")
+			if err := m.apply(ctx, conn, mig, true); err != nil {
+				return err
+			}
+			applied = append(applied, mig)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the last steps applied migrations and returns those reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		done, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := done[mig.Version]; !ok {
+				continue
+			}
+			m.logger.With("version", mig.Version).With("name", mig.Name).Info("reverting migration")
+			if err := m.apply(ctx, conn, mig, false); err != nil {
+				return err
+			}
+			reverted = append(reverted, mig)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status lists every known migration and when it was applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, m.dialect.CreateTable); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	done, err := m.appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, mig := range m.migrations {
+		statuses[i] = Status{Migration: mig}
+		if at, ok := done[mig.Version]; ok {
+			statuses[i].AppliedAt = &at
+		}
+	}
+	return statuses, nil
+}
+
+// Pending reports how many migrations have not been applied yet.
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+	pending := 0
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// locked runs fn on a dedicated connection holding the migration lock, so
+// replicas starting concurrently apply each migration exactly once.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	unlock, err := m.dialect.Lock(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		if err := unlock(); err != nil {
+			m.logger.Error("failed to release migration lock", err)
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, m.dialect.CreateTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return fn(conn)
+}
+
+// apply runs one direction of a migration and records it in a single transaction.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mig Migration, up bool) (err error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	script, record, args := mig.Up, m.dialect.Insert, []any{mig.Version, mig.Name}
+	if !up {
+		script, record, args = mig.Down, m.dialect.Delete, []any{mig.Version}
+	}
+
+	if _, err = tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("migration %d_%s failed: %w", mig.Version, mig.Name, err)
+	}
+	if _, err = tx.ExecContext(ctx, record, args...); err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", mig.Version, err)
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	done := make(map[int64]time.Time)
+	for rows.Next() {
+		var (
+			version   int64
+			appliedAt time.Time
+		)
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		done[version] = appliedAt
+	}
+	return done, rows.Err()
+}
+
+// load reads and pairs the migration files of fsys.
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = mig
+		} else if mig.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, mig.Name, match[2])
+		}
+		if match[3] == "up" {
+			mig.Up = string(content)
+		} else {
+			mig.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+var validName = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// Create writes an empty up/down pair for a new migration named name into
+// dir/<dialect> of every dialect and returns the created paths. The version
+// is one above the highest existing version of any dialect, so the
+// dialects stay in step.
+func Create(dir string, dialects []Dialect, name string) ([]string, error) {
+	if !validName.MatchString(name) {
+		return nil, errors.New("migration name must match [a-z0-9_]+")
+	}
+
+	next := int64(1)
+	for _, dialect := range dialects {
+		target := filepath.Join(dir, dialect.Name)
+		if _, err := os.Stat(target); err != nil {
+			return nil, fmt.Errorf("migration directory %s not found, run from the module root: %w", target, err)
+		}
+		existing, err := load(os.DirFS(target))
+		if err != nil {
+			return nil, err
+		}
+		if n := len(existing); n > 0 {
+			next = max(next, existing[n-1].Version+1)
+		}
+	}
+
+	var paths []string
+	for _, dialect := range dialects {
+		for _, direction := range []string{"up", "down"} {
+			path := filepath.Join(dir, dialect.Name, fmt.Sprintf("%04d_%s.%s.sql", next, name, direction))
+			f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+			if err != nil {
+				return paths, err
+			}
+			if _, err := fmt.Fprintf(f, "-- %04d_%s (%s, %s)\n", next, name, dialect.Name, direction); err != nil {
+				f.Close()
+				return paths, err
+			}
+			if err := f.Close(); err != nil {
+				return paths, err
+			}
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
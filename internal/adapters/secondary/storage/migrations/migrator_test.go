@@ -0,0 +1,174 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	// Registers the "sqlite" database/sql driver.
+	_ "github.com/glebarez/go-sqlite"
+
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/logger"
+)
+
+func newSQLiteMigrator(t *testing.T) *Migrator {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "items.db"))
+	if err != nil {
+		t.Fatalf("open SQLite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	m, err := New(db, SQLite, logger.New(logger.WithOutput(io.Discard)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return m
+}
+
+func expectPending(t *testing.T, m *Migrator, want int) {
+	t.Helper()
+	pending, err := m.Pending(context.Background())
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if pending != want {
+		t.Errorf("%d migrations pending, want %d", pending, want)
+	}
+}
+
+func TestUpAndDown(t *testing.T) {
+	ctx := context.Background()
+	m := newSQLiteMigrator(t)
+	total := len(m.migrations)
+	expectPending(t, m, total)
+
+	applied, err := m.Up(ctx)
+	if err != nil || len(applied) != total {
+		t.Fatalf("Up applied %d migrations, %v, want %d", len(applied), err, total)
+	}
+	if _, err := m.db.ExecContext(ctx, "INSERT INTO items (title, user_id) VALUES ('migrated', 'alice')"); err != nil {
+		t.Errorf("items table is unusable after Up: %v", err)
+	}
+	expectPending(t, m, 0)
+	if applied, err := m.Up(ctx); err != nil || len(applied) != 0 {
+		t.Errorf("second Up applied %d migrations, %v, want none", len(applied), err)
+	}
+
+	reverted, err := m.Down(ctx, 1)
+	if err != nil || len(reverted) != 1 || reverted[0].Version != m.migrations[total-1].Version {
+		t.Fatalf("Down(1) reverted %+v, %v, want the last migration", reverted, err)
+	}
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	for i, s := range statuses {
+		if applied := s.AppliedAt != nil; applied != (i < total-1) {
+			t.Errorf("migration %d applied: %v after Down(1)", s.Version, applied)
+		}
+	}
+
+	if reverted, err := m.Down(ctx, total); err != nil || len(reverted) != total-1 {
+		t.Errorf("Down(all) reverted %d migrations, %v, want %d", len(reverted), err, total-1)
+	}
+	expectPending(t, m, total)
+	if applied, err := m.Up(ctx); err != nil || len(applied) != total {
+		t.Errorf("Up after reverting everything applied %d migrations, %v, want %d", len(applied), err, total)
+	}
+}
+
+func TestDialectsStayInStep(t *testing.T) {
+	var first []Migration
+	for i, dialect := range Dialects {
+		m, err := New(nil, dialect, logger.New(logger.WithOutput(io.Discard)))
+		if err != nil {
+			t.Fatalf("load %s migrations: %v", dialect.Name, err)
+		}
+		if i == 0 {
+			first = m.migrations
+			continue
+		}
+		if len(m.migrations) != len(first) {
+			t.Fatalf("%s has %d migrations, %s has %d", dialect.Name, len(m.migrations), Dialects[0].Name, len(first))
+		}
+		for j, mig := range m.migrations {
+			if mig.Version != first[j].Version || mig.Name != first[j].Name {
+				t.Errorf("%s migration %d_%s differs from %d_%s", dialect.Name, mig.Version, mig.Name, first[j].Version, first[j].Name)
+			}
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	file := func(s string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(s)} }
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		want    []int64
+		wantErr bool
+	}{
+		{"sorted by version", fstest.MapFS{
+			"0010_b.up.sql": file("b"), "0010_b.down.sql": file("b"),
+			"0002_a.up.sql": file("a"), "0002_a.down.sql": file("a"),
+			"README.md": file("ignored"),
+		}, []int64{2, 10}, false},
+		{"missing down", fstest.MapFS{"0001_a.up.sql": file("a")}, nil, true},
+		{"conflicting names", fstest.MapFS{"0001_a.up.sql": file("a"), "0001_b.down.sql": file("b")}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := load(tt.fsys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("load returned %v", err)
+			}
+			if len(migrations) != len(tt.want) {
+				t.Fatalf("load returned %d migrations, want %d", len(migrations), len(tt.want))
+			}
+			for i, mig := range migrations {
+				if mig.Version != tt.want[i] {
+					t.Errorf("migration %d has version %d, want %d", i, mig.Version, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	for _, dialect := range Dialects {
+		if err := os.Mkdir(filepath.Join(dir, dialect.Name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The next version follows the highest of any dialect.
+	for name, content := range map[string]string{"0003_a.up.sql": "a", "0003_a.down.sql": "a"} {
+		if err := os.WriteFile(filepath.Join(dir, Dialects[0].Name, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	paths, err := Create(dir, Dialects, "add_tags")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if len(paths) != 2*len(Dialects) {
+		t.Errorf("Create wrote %d files, want %d", len(paths), 2*len(Dialects))
+	}
+	for _, dialect := range Dialects {
+		migrations, err := load(os.DirFS(filepath.Join(dir, dialect.Name)))
+		if err != nil {
+			t.Fatalf("load %s: %v", dialect.Name, err)
+		}
+		if last := migrations[len(migrations)-1]; last.Version != 4 || last.Name != "add_tags" {
+			t.Errorf("%s ends with %d_%s, want 4_add_tags", dialect.Name, last.Version, last.Name)
+		}
+	}
+
+	if _, err := Create(dir, Dialects, "Add Tags"); err == nil {
+		t.Error("Create accepted an invalid name")
+	}
+}
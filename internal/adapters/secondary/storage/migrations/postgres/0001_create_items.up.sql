@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS items (
+    id          BIGSERIAL   PRIMARY KEY,
+    title       TEXT        NOT NULL,
+    description TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    deleted_at  TIMESTAMPTZ,
+    user_id     TEXT        NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_items_user_id ON items (user_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_items_user_created;
+DROP INDEX IF EXISTS idx_items_deleted_at;
+
+ALTER TABLE items DROP COLUMN IF EXISTS version;
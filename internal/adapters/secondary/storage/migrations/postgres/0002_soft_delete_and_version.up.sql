@@ -0,0 +1,8 @@
+-- Rows written before soft deletes existed carry the zero time instead of NULL.
+ALTER TABLE items ALTER COLUMN deleted_at DROP NOT NULL;
+UPDATE items SET deleted_at = NULL WHERE deleted_at < '0002-01-01';
+
+ALTER TABLE items ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+CREATE INDEX IF NOT EXISTS idx_items_deleted_at ON items (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_items_user_created ON items (user_id, created_at, id) WHERE deleted_at IS NULL;
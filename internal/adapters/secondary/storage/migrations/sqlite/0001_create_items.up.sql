@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS items (
+    id          INTEGER   PRIMARY KEY AUTOINCREMENT,
+    title       TEXT      NOT NULL,
+    description TEXT,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at  TIMESTAMP,
+    user_id     TEXT      NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_items_user_id ON items (user_id);
@@ -0,0 +1,5 @@
+-- deleted_at is nullable from the start in SQLite; only the version is new.
+ALTER TABLE items ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+CREATE INDEX IF NOT EXISTS idx_items_deleted_at ON items (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_items_user_created ON items (user_id, created_at, id) WHERE deleted_at IS NULL;
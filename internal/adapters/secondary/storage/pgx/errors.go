@@ -0,0 +1,60 @@
+package pgx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+)
+
+// PostgreSQL error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+	pgQueryCanceled        = "57014"
+)
+
+// translateError maps pgx and PostgreSQL errors to domain errors the same
+// way the GORM adapter does. The original error stays in the chain for
+// logging.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrItemNotFound
+	}
+
+	// A deadline cancels the query; a cancelled context means the caller,
+	// usually a disconnected client, gave up.
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", domain.ErrTimeout, err)
+	}
+	if errors.Is(err, context.Canceled) {
+		return fmt.Errorf("%w: %w", domain.ErrUnavailable, err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return fmt.Errorf("%w: %w", domain.ErrItemExists, err)
+		case pgForeignKeyViolation:
+			return fmt.Errorf("%w: %w", domain.ErrInvalidReference, err)
+		case pgSerializationFailure, pgDeadlockDetected:
+			return fmt.Errorf("%w: %w: %w", domain.ErrUnavailable, domain.ErrTxConflict, err)
+		case pgQueryCanceled:
+			// Raised by statement_timeout.
+			return fmt.Errorf("%w: %w", domain.ErrTimeout, err)
+		}
+	}
+
+	return err
+}
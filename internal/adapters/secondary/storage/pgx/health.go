@@ -0,0 +1,27 @@
+package pgx
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+// databaseChecker implements ports.HealthChecker by pinging the connection pool.
+type databaseChecker struct {
+	pool *pgxpool.Pool
+}
+
+// NewDatabaseChecker returns a health checker for the database behind pool.
+func NewDatabaseChecker(pool *pgxpool.Pool) ports.HealthChecker {
+	return &databaseChecker{pool: pool}
+}
+
+func (c *databaseChecker) Name() string {
+	return "database"
+}
+
+func (c *databaseChecker) Check(ctx context.Context) error {
+	return c.pool.Ping(ctx)
+}
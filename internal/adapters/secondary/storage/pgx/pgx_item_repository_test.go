@@ -0,0 +1,51 @@
+package pgx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/storage/storagetest"
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+// openPool returns a pool on a fresh schema of storagetest.PostgresEnv, or
+// skips tb.
+func openPool(tb testing.TB) *pgxpool.Pool {
+	tb.Helper()
+	pool, err := pgxpool.New(context.Background(), storagetest.PostgresDSN(tb))
+	if err != nil {
+		tb.Fatalf("open PostgreSQL: %v", err)
+	}
+	tb.Cleanup(pool.Close)
+	return pool
+}
+
+func TestContract(t *testing.T) {
+	storagetest.RunItemRepositoryContract(t, func(t *testing.T) ports.ItemRepository {
+		return NewPgxItemRepository(openPool(t))
+	})
+}
+
+func TestTxManagerContract(t *testing.T) {
+	storagetest.RunTxManagerContract(t, func(t *testing.T) (ports.ItemRepository, ports.TxManager) {
+		pool := openPool(t)
+		return NewPgxItemRepository(pool), NewTxManager(pool, sql.LevelSerializable)
+	})
+	storagetest.RunTxConflictContract(t, func(t *testing.T) (ports.ItemRepository, ports.TxManager) {
+		pool := openPool(t)
+		return NewPgxItemRepository(pool), NewTxManager(pool, sql.LevelRepeatableRead)
+	})
+}
+
+func BenchmarkRepository(b *testing.B) {
+	pool := openPool(b)
+	storagetest.RunItemRepositoryBenchmarks(b, func(b *testing.B) ports.ItemRepository {
+		if _, err := pool.Exec(context.Background(), "TRUNCATE items RESTART IDENTITY"); err != nil {
+			b.Fatalf("empty items: %v", err)
+		}
+		return NewPgxItemRepository(pool)
+	})
+}
@@ -0,0 +1,103 @@
+package pgx
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// queryTracer wraps every query, batch and copy in a client span, a child of
+// the span carried by the query context.
+type queryTracer struct {
+	tracer trace.Tracer
+}
+
+var (
+	_ pgx.QueryTracer    = (*queryTracer)(nil)
+	_ pgx.BatchTracer    = (*queryTracer)(nil)
+	_ pgx.CopyFromTracer = (*queryTracer)(nil)
+)
+
+// NewQueryTracer returns a pgx tracer creating spans with provider. Set it
+// as the Tracer of the pool's connection config.
+func NewQueryTracer(provider trace.TracerProvider) pgx.QueryTracer {
+	return &queryTracer{
+		tracer: provider.Tracer("github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/storage/pgx"),
+	}
+}
+
+func (t *queryTracer) start(ctx context.Context, name string, attrs ...attribute.KeyValue) context.Context {
+	ctx, _ = t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, attribute.String("db.system.name", "postgresql"))...),
+	)
+	return ctx
+}
+
+func end(ctx context.Context, rowsAffected int64, err error) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Int64("db.response.affected_rows", rowsAffected))
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// operation returns the lower-cased leading keyword of sql.
+func operation(sql string) string {
+	keyword, _, _ := strings.Cut(strings.TrimSpace(sql), " ")
+	return strings.ToLower(keyword)
+}
+
+func (t *queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	// The query text holds placeholders only; bound values are not recorded.
+	op := operation(data.SQL)
+	return t.start(ctx, op,
+		attribute.String("db.operation.name", op),
+		attribute.String("db.query.text", data.SQL),
+	)
+}
+
+func (t *queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	end(ctx, data.CommandTag.RowsAffected(), data.Err)
+}
+
+func (t *queryTracer) TraceBatchStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchStartData) context.Context {
+	return t.start(ctx, "batch",
+		attribute.String("db.operation.name", "batch"),
+		attribute.Int("db.operation.batch.size", data.Batch.Len()),
+	)
+}
+
+func (t *queryTracer) TraceBatchQuery(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchQueryData) {
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("query", trace.WithAttributes(
+		attribute.String("db.query.text", data.SQL),
+		attribute.Int64("db.response.affected_rows", data.CommandTag.RowsAffected()),
+	))
+	if data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows) {
+		span.RecordError(data.Err)
+	}
+}
+
+func (t *queryTracer) TraceBatchEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchEndData) {
+	end(ctx, 0, data.Err)
+}
+
+func (t *queryTracer) TraceCopyFromStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceCopyFromStartData) context.Context {
+	table := strings.Join(data.TableName, ".")
+	return t.start(ctx, "copy "+table,
+		attribute.String("db.operation.name", "copy"),
+		attribute.String("db.collection.name", table),
+	)
+}
+
+func (t *queryTracer) TraceCopyFromEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceCopyFromEndData) {
+	end(ctx, data.CommandTag.RowsAffected(), data.Err)
+}
@@ -0,0 +1,60 @@
+package pgx
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// txKey is the context key of the transaction a PgxItemRepository uses.
+type txKey struct{}
+
+// TxManager runs functions in transactions of a pgx pool. Repositories on the
+// same pool use the transaction of the context they are called with.
+type TxManager struct {
+	pool *pgxpool.Pool
+	opts pgx.TxOptions
+}
+
+// isoLevels maps the database/sql isolation levels to those of PostgreSQL.
+var isoLevels = map[sql.IsolationLevel]pgx.TxIsoLevel{
+	sql.LevelReadUncommitted: pgx.ReadUncommitted,
+	sql.LevelReadCommitted:   pgx.ReadCommitted,
+	sql.LevelRepeatableRead:  pgx.RepeatableRead,
+	sql.LevelSerializable:    pgx.Serializable,
+}
+
+// NewTxManager returns a TxManager starting transactions on pool at
+// isolation; sql.LevelDefault, like any level PostgreSQL lacks, keeps the
+// default of the database.
+func NewTxManager(pool *pgxpool.Pool, isolation sql.IsolationLevel) *TxManager {
+	return &TxManager{pool: pool, opts: pgx.TxOptions{IsoLevel: isoLevels[isolation]}}
+}
+
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	// Errors of fn are already translated by the repository; only those of
+	// beginning and committing the transaction are left.
+	var fnErr error
+	err := pgx.BeginTxFunc(ctx, m.pool, m.opts, func(tx pgx.Tx) error {
+		fnErr = fn(context.WithValue(ctx, txKey{}, tx))
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	return translateError(err)
+}
+
+// conn returns the transaction ctx carries, or the pool of r outside of one.
+func (r *PgxItemRepository) conn(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return r.db
+}
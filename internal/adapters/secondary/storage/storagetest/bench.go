@@ -0,0 +1,178 @@
+package storagetest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+// benchmark is one operation of the repository workload.
+type benchmark struct {
+	Name string
+	Run  func(b *testing.B, repo ports.ItemRepository)
+}
+
+// Sizes of the benchmark workload.
+const (
+	// benchItems items are stored for the benchmarked user, and as many for
+	// another user, before the timer starts.
+	benchItems = 1000
+	benchBatch = 100
+	benchUser  = "bench"
+)
+
+// benchmarks is the workload every repository adapter is measured with, so
+// the adapters compare on equal terms. Each benchmark expects an empty
+// repository and seeds it itself.
+var benchmarks = []benchmark{
+	{"Create", benchCreate},
+	{"CreateMany", benchCreateMany},
+	{"GetByID", benchGetByID},
+	{"ListFirstPage", benchListFirstPage},
+	{"ListPageAfterCursor", benchListAfterCursor},
+	{"ListFilteredByTitle", benchListFiltered},
+	{"Update", benchUpdate},
+}
+
+// RunItemRepositoryBenchmarks runs the workload as sub-benchmarks of b, each
+// against a repository returned by newRepo:
+//
+//	func BenchmarkRepository(b *testing.B) {
+//		storagetest.RunItemRepositoryBenchmarks(b, func(b *testing.B) ports.ItemRepository {
+//			return memory.NewMemoryItemRepository()
+//		})
+//	}
+func RunItemRepositoryBenchmarks(b *testing.B, newRepo func(b *testing.B) ports.ItemRepository) {
+	for _, bm := range benchmarks {
+		b.Run(bm.Name, func(b *testing.B) {
+			bm.Run(b, newRepo(b))
+		})
+	}
+}
+
+func newItems(userID string, n int) []*domain.Item {
+	items := make([]*domain.Item, n)
+	for i := range items {
+		items[i] = &domain.Item{
+			UserID:      userID,
+			Title:       ptr(fmt.Sprintf("item %05d", i)),
+			Description: ptr("a description long enough to resemble what users write about their items"),
+		}
+	}
+	return items
+}
+
+// seed stores the workload items and returns those of the benchmarked user.
+func seed(b *testing.B, repo ports.ItemRepository) []*domain.Item {
+	b.Helper()
+	ctx := context.Background()
+	items := newItems(benchUser, benchItems)
+	if err := repo.CreateMany(ctx, items); err != nil {
+		b.Fatalf("seeding: %v", err)
+	}
+	if err := repo.CreateMany(ctx, newItems("other", benchItems)); err != nil {
+		b.Fatalf("seeding: %v", err)
+	}
+	return items
+}
+
+func benchCreate(b *testing.B, repo ports.ItemRepository) {
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		item := &domain.Item{UserID: benchUser, Title: ptr("created"), Description: ptr("created by the benchmark")}
+		if err := repo.Create(ctx, item); err != nil {
+			b.Fatalf("Create: %v", err)
+		}
+	}
+}
+
+func benchCreateMany(b *testing.B, repo ports.ItemRepository) {
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := repo.CreateMany(ctx, newItems(benchUser, benchBatch)); err != nil {
+			b.Fatalf("CreateMany: %v", err)
+		}
+	}
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*benchBatch), "ns/item")
+}
+
+func benchGetByID(b *testing.B, repo ports.ItemRepository) {
+	ctx := context.Background()
+	items := seed(b, repo)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetByID(ctx, benchUser, items[i%len(items)].ID); err != nil {
+			b.Fatalf("GetByID: %v", err)
+		}
+	}
+}
+
+func benchListFirstPage(b *testing.B, repo ports.ItemRepository) {
+	ctx := context.Background()
+	seed(b, repo)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetByUserID(ctx, benchUser, domain.ItemQuery{Limit: domain.MaxPageLimit}); err != nil {
+			b.Fatalf("GetByUserID: %v", err)
+		}
+	}
+}
+
+func benchListAfterCursor(b *testing.B, repo ports.ItemRepository) {
+	ctx := context.Background()
+	seed(b, repo)
+	// Start halfway through the listing.
+	query := domain.ItemQuery{Limit: domain.MaxPageLimit, SortBy: domain.SortByTitle, SortDir: domain.SortAsc}
+	for seen := 0; seen < benchItems/2; seen += query.Limit {
+		page, err := repo.GetByUserID(ctx, benchUser, query)
+		if err != nil {
+			b.Fatalf("GetByUserID: %v", err)
+		}
+		query.Cursor = page.NextCursor
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetByUserID(ctx, benchUser, query); err != nil {
+			b.Fatalf("GetByUserID: %v", err)
+		}
+	}
+}
+
+func benchListFiltered(b *testing.B, repo ports.ItemRepository) {
+	ctx := context.Background()
+	seed(b, repo)
+	query := domain.ItemQuery{ItemFilter: domain.ItemFilter{TitlePrefix: "item 001"}}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetByUserID(ctx, benchUser, query); err != nil {
+			b.Fatalf("GetByUserID: %v", err)
+		}
+	}
+}
+
+func benchUpdate(b *testing.B, repo ports.ItemRepository) {
+	ctx := context.Background()
+	items := seed(b, repo)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Update reloads the item, so it carries the version to match next.
+		item := items[i%len(items)]
+		item.Title = ptr(fmt.Sprintf("updated %d", i))
+		if err := repo.Update(ctx, item, []string{domain.ItemFieldTitle}); err != nil {
+			b.Fatalf("Update: %v", err)
+		}
+	}
+}
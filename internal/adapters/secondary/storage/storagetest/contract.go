@@ -0,0 +1,532 @@
+// Package storagetest holds the behaviour every ports.ItemRepository
+// adapter must share, as a suite the adapters' tests run against their own
+// store.
+package storagetest
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+// Factory returns an empty repository for one test. It registers any
+// cleanup with t.Cleanup.
+type Factory func(t *testing.T) ports.ItemRepository
+
+// RunItemRepositoryContract runs the repository contract against the
+// repositories returned by newRepo:
+//
+//	func TestContract(t *testing.T) {
+//		storagetest.RunItemRepositoryContract(t, func(t *testing.T) ports.ItemRepository {
+//			return memory.NewMemoryItemRepository()
+//		})
+//	}
+func RunItemRepositoryContract(t *testing.T, newRepo Factory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, repo ports.ItemRepository)
+	}{
+		{"Create", testCreate},
+		{"CreateMany", testCreateMany},
+		{"GetByID", testGetByID},
+		{"Update", testUpdate},
+		{"UpdateMany", testUpdateMany},
+		{"Delete", testDelete},
+		{"DeleteMany", testDeleteMany},
+		{"Restore", testRestore},
+		{"Purge", testPurge},
+		{"List", testList},
+		{"Paginate", testPaginate},
+		{"Filter", testFilter},
+		{"DoneContext", testDoneContext},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newRepo(t))
+		})
+	}
+}
+
+const (
+	alice = "alice"
+	bob   = "bob"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// create stores a new item and fails the test on error.
+func create(t *testing.T, repo ports.ItemRepository, userID, title string) *domain.Item {
+	t.Helper()
+	item := &domain.Item{UserID: userID, Title: ptr(title), Description: ptr("about " + title)}
+	if err := repo.Create(context.Background(), item); err != nil {
+		t.Fatalf("Create(%q): %v", title, err)
+	}
+	return item
+}
+
+func expectError(t *testing.T, op string, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Fatalf("%s: got error %v, want %v", op, err, want)
+	}
+}
+
+func expectNoError(t *testing.T, op string, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s: %v", op, err)
+	}
+}
+
+func testCreate(t *testing.T, repo ports.ItemRepository) {
+	before := time.Now().Add(-time.Second)
+	first := create(t, repo, alice, "first")
+	second := create(t, repo, alice, "second")
+
+	if first.ID == 0 || second.ID == 0 || first.ID == second.ID {
+		t.Fatalf("expected distinct non-zero IDs, got %d and %d", first.ID, second.ID)
+	}
+	if first.Version != 1 {
+		t.Errorf("expected version 1, got %d", first.Version)
+	}
+	if first.CreatedAt.Before(before) || first.UpdatedAt.Before(before) {
+		t.Errorf("expected timestamps to be set, got created %v, updated %v", first.CreatedAt, first.UpdatedAt)
+	}
+	if first.DeletedAt != nil {
+		t.Errorf("expected a new item not to be deleted")
+	}
+
+	duplicate := &domain.Item{ID: first.ID, UserID: alice, Title: ptr("duplicate")}
+	expectError(t, "Create with a taken ID", repo.Create(context.Background(), duplicate), domain.ErrItemExists)
+}
+
+func testCreateMany(t *testing.T, repo ports.ItemRepository) {
+	ctx := context.Background()
+	expectNoError(t, "CreateMany without items", repo.CreateMany(ctx, nil))
+
+	items := make([]*domain.Item, 5)
+	for i := range items {
+		items[i] = &domain.Item{UserID: alice, Title: ptr(fmt.Sprintf("bulk %d", i))}
+	}
+	items[2].Description = ptr("described")
+	expectNoError(t, "CreateMany", repo.CreateMany(ctx, items))
+
+	ids := make(map[int64]bool)
+	for _, item := range items {
+		if item.ID == 0 || ids[item.ID] {
+			t.Fatalf("expected distinct non-zero IDs, got %d", item.ID)
+		}
+		ids[item.ID] = true
+		if item.Version != 1 || item.CreatedAt.IsZero() || item.UpdatedAt.IsZero() {
+			t.Errorf("item %d has version %d, created %v, updated %v", item.ID, item.Version, item.CreatedAt, item.UpdatedAt)
+		}
+		got, err := repo.GetByID(ctx, alice, item.ID)
+		expectNoError(t, "GetByID", err)
+		if *got.Title != *item.Title || (got.Description == nil) != (item.Description == nil) {
+			t.Errorf("stored item %d is %+v, want %+v", item.ID, got, item)
+		}
+	}
+
+	// A taken ID fails the whole batch.
+	batch := []*domain.Item{
+		{UserID: bob, Title: ptr("fresh")},
+		{ID: items[0].ID, UserID: bob, Title: ptr("taken")},
+	}
+	expectError(t, "CreateMany with a taken ID", repo.CreateMany(ctx, batch), domain.ErrItemExists)
+	page, err := repo.GetByUserID(ctx, bob, domain.ItemQuery{})
+	expectNoError(t, "GetByUserID", err)
+	if len(page.Items) != 0 {
+		t.Errorf("a failed batch stored %d items", len(page.Items))
+	}
+}
+
+func testGetByID(t *testing.T, repo ports.ItemRepository) {
+	ctx := context.Background()
+	item := create(t, repo, alice, "mine")
+
+	got, err := repo.GetByID(ctx, alice, item.ID)
+	expectNoError(t, "GetByID", err)
+	if got.ID != item.ID || *got.Title != "mine" || *got.Description != "about mine" || got.UserID != alice {
+		t.Errorf("GetByID returned %+v, want %+v", got, item)
+	}
+	if !got.CreatedAt.Equal(item.CreatedAt) || got.Version != item.Version {
+		t.Errorf("GetByID returned created %v version %d, want %v and %d", got.CreatedAt, got.Version, item.CreatedAt, item.Version)
+	}
+
+	// Changing the returned item must not change the stored one.
+	*got.Title = "changed"
+	again, err := repo.GetByID(ctx, alice, item.ID)
+	expectNoError(t, "GetByID", err)
+	if *again.Title != "mine" {
+		t.Errorf("stored item changed through a returned copy")
+	}
+
+	_, err = repo.GetByID(ctx, bob, item.ID)
+	expectError(t, "GetByID of another user's item", err, domain.ErrItemNotOwned)
+	_, err = repo.GetByID(ctx, alice, item.ID+1000)
+	expectError(t, "GetByID of a missing item", err, domain.ErrItemNotFound)
+}
+
+func testUpdate(t *testing.T, repo ports.ItemRepository) {
+	ctx := context.Background()
+	item := create(t, repo, alice, "before")
+
+	update := *item
+	update.Title = ptr("after")
+	update.Description = ptr("ignored")
+	expectNoError(t, "Update", repo.Update(ctx, &update, []string{domain.ItemFieldTitle}))
+	if *update.Title != "after" || *update.Description != "about before" {
+		t.Errorf("Update reloaded title %q, description %q", *update.Title, *update.Description)
+	}
+	if update.Version != item.Version+1 {
+		t.Errorf("expected version %d, got %d", item.Version+1, update.Version)
+	}
+	if update.UpdatedAt.Before(item.UpdatedAt) {
+		t.Errorf("expected updated_at to move forward")
+	}
+
+	stale := *item
+	stale.Title = ptr("stale")
+	expectError(t, "Update at a stale version", repo.Update(ctx, &stale, []string{domain.ItemFieldTitle}), domain.ErrVersionMismatch)
+
+	foreign := update
+	foreign.UserID = bob
+	expectError(t, "Update of another user's item", repo.Update(ctx, &foreign, []string{domain.ItemFieldTitle}), domain.ErrItemNotFound)
+
+	expectError(t, "Update without fields", repo.Update(ctx, &update, nil), domain.ErrInvalidItem)
+	expectError(t, "Update of an immutable field", repo.Update(ctx, &update, []string{domain.ItemFieldUserID}), domain.ErrInvalidItem)
+
+	cleared := update
+	cleared.Description = nil
+	expectNoError(t, "Update clearing description", repo.Update(ctx, &cleared, []string{domain.ItemFieldDescription}))
+	if cleared.Description != nil {
+		t.Errorf("expected description to be cleared, got %q", *cleared.Description)
+	}
+}
+
+// expectBatchError checks that err reports the item at index failing with want.
+func expectBatchError(t *testing.T, op string, err error, index int, want error) {
+	t.Helper()
+	var itemErr *domain.BatchItemError
+	if !errors.As(err, &itemErr) || itemErr.Index != index || !errors.Is(err, want) {
+		t.Fatalf("%s: got error %v, want %v at item %d", op, err, want, index)
+	}
+}
+
+func testUpdateMany(t *testing.T, repo ports.ItemRepository) {
+	ctx := context.Background()
+	fields := []string{domain.ItemFieldTitle}
+	expectNoError(t, "UpdateMany without items", repo.UpdateMany(ctx, nil, fields))
+
+	first := create(t, repo, alice, "first")
+	second := create(t, repo, alice, "second")
+
+	a, b := *first, *second
+	a.Title, b.Title = ptr("first updated"), ptr("second updated")
+	expectNoError(t, "UpdateMany", repo.UpdateMany(ctx, []*domain.Item{&a, &b}, fields))
+	if *a.Title != "first updated" || a.Version != first.Version+1 || b.Version != second.Version+1 {
+		t.Errorf("UpdateMany reloaded %q at version %d and version %d", *a.Title, a.Version, b.Version)
+	}
+
+	// A stale item fails the whole batch.
+	fresh, stale := a, *second
+	fresh.Title, stale.Title = ptr("lost"), ptr("stale")
+	err := repo.UpdateMany(ctx, []*domain.Item{&fresh, &stale}, fields)
+	expectBatchError(t, "UpdateMany with a stale item", err, 1, domain.ErrVersionMismatch)
+	got, err := repo.GetByID(ctx, alice, first.ID)
+	expectNoError(t, "GetByID", err)
+	if *got.Title != "first updated" || got.Version != a.Version {
+		t.Errorf("a failed batch left title %q at version %d", *got.Title, got.Version)
+	}
+
+	foreign := b
+	foreign.UserID = bob
+	err = repo.UpdateMany(ctx, []*domain.Item{&foreign}, fields)
+	expectBatchError(t, "UpdateMany of another user's item", err, 0, domain.ErrItemNotFound)
+}
+
+func testDelete(t *testing.T, repo ports.ItemRepository) {
+	ctx := context.Background()
+	item := create(t, repo, alice, "doomed")
+
+	expectError(t, "Delete at a stale version", repo.Delete(ctx, alice, item.ID, item.Version+1), domain.ErrVersionMismatch)
+	expectError(t, "Delete of another user's item", repo.Delete(ctx, bob, item.ID, 0), domain.ErrItemNotFound)
+	expectNoError(t, "Delete", repo.Delete(ctx, alice, item.ID, item.Version))
+
+	_, err := repo.GetByID(ctx, alice, item.ID)
+	expectError(t, "GetByID of a deleted item", err, domain.ErrItemNotFound)
+	expectError(t, "Delete of a deleted item", repo.Delete(ctx, alice, item.ID, 0), domain.ErrItemNotFound)
+
+	page, err := repo.GetDeleted(ctx, domain.ItemQuery{})
+	expectNoError(t, "GetDeleted", err)
+	if len(page.Items) != 1 || page.Items[0].ID != item.ID {
+		t.Fatalf("GetDeleted returned %d items, want the deleted item", len(page.Items))
+	}
+	if page.Items[0].DeletedAt == nil || page.Items[0].Version != item.Version+1 {
+		t.Errorf("deleted item has deleted_at %v and version %d", page.Items[0].DeletedAt, page.Items[0].Version)
+	}
+
+	page, err = repo.GetAll(ctx, domain.ItemQuery{})
+	expectNoError(t, "GetAll", err)
+	if len(page.Items) != 0 {
+		t.Errorf("GetAll returned %d items, want none", len(page.Items))
+	}
+}
+
+func testDeleteMany(t *testing.T, repo ports.ItemRepository) {
+	ctx := context.Background()
+	expectNoError(t, "DeleteMany without items", repo.DeleteMany(ctx, alice, nil))
+
+	first := create(t, repo, alice, "first")
+	second := create(t, repo, alice, "second")
+	third := create(t, repo, alice, "third")
+
+	// A missing item fails the whole batch.
+	err := repo.DeleteMany(ctx, alice, []domain.ItemRef{{ID: first.ID}, {ID: third.ID + 1000}})
+	expectBatchError(t, "DeleteMany with a missing item", err, 1, domain.ErrItemNotFound)
+	got, err := repo.GetByID(ctx, alice, first.ID)
+	expectNoError(t, "GetByID after a failed batch", err)
+	if got.Version != first.Version {
+		t.Errorf("a failed batch left version %d, want %d", got.Version, first.Version)
+	}
+
+	err = repo.DeleteMany(ctx, alice, []domain.ItemRef{{ID: first.ID}, {ID: second.ID, Version: second.Version + 1}})
+	expectBatchError(t, "DeleteMany with a stale item", err, 1, domain.ErrVersionMismatch)
+	err = repo.DeleteMany(ctx, bob, []domain.ItemRef{{ID: first.ID}})
+	expectBatchError(t, "DeleteMany of another user's item", err, 0, domain.ErrItemNotFound)
+
+	expectNoError(t, "DeleteMany", repo.DeleteMany(ctx, alice, []domain.ItemRef{{ID: first.ID, Version: first.Version}, {ID: second.ID}}))
+	page, err := repo.GetByUserID(ctx, alice, domain.ItemQuery{})
+	expectNoError(t, "GetByUserID", err)
+	if len(page.Items) != 1 || page.Items[0].ID != third.ID {
+		t.Errorf("GetByUserID returned %d items, want only the third", len(page.Items))
+	}
+}
+
+func testRestore(t *testing.T, repo ports.ItemRepository) {
+	ctx := context.Background()
+	item := create(t, repo, alice, "back")
+
+	_, err := repo.Restore(ctx, alice, item.ID)
+	expectError(t, "Restore of an item that is not deleted", err, domain.ErrItemNotFound)
+
+	expectNoError(t, "Delete", repo.Delete(ctx, alice, item.ID, 0))
+	_, err = repo.Restore(ctx, bob, item.ID)
+	expectError(t, "Restore of another user's item", err, domain.ErrItemNotFound)
+
+	restored, err := repo.Restore(ctx, alice, item.ID)
+	expectNoError(t, "Restore", err)
+	if restored.DeletedAt != nil || restored.Version != item.Version+2 {
+		t.Errorf("restored item has deleted_at %v and version %d", restored.DeletedAt, restored.Version)
+	}
+	_, err = repo.GetByID(ctx, alice, item.ID)
+	expectNoError(t, "GetByID of a restored item", err)
+}
+
+func testPurge(t *testing.T, repo ports.ItemRepository) {
+	ctx := context.Background()
+	kept := create(t, repo, alice, "kept")
+	trashed := create(t, repo, alice, "trashed")
+	expectNoError(t, "Delete", repo.Delete(ctx, alice, trashed.ID, 0))
+
+	purged, err := repo.Purge(ctx, time.Now().Add(-time.Hour))
+	expectNoError(t, "Purge", err)
+	if purged != 0 {
+		t.Errorf("Purge before the deletion removed %d items", purged)
+	}
+
+	purged, err = repo.Purge(ctx, time.Now().Add(time.Hour))
+	expectNoError(t, "Purge", err)
+	if purged != 1 {
+		t.Errorf("Purge removed %d items, want 1", purged)
+	}
+	_, err = repo.Restore(ctx, alice, trashed.ID)
+	expectError(t, "Restore of a purged item", err, domain.ErrItemNotFound)
+	_, err = repo.GetByID(ctx, alice, kept.ID)
+	expectNoError(t, "GetByID of an item that is not deleted", err)
+}
+
+func testList(t *testing.T, repo ports.ItemRepository) {
+	ctx := context.Background()
+	create(t, repo, alice, "a1")
+	create(t, repo, alice, "a2")
+	create(t, repo, bob, "b1")
+
+	page, err := repo.GetAll(ctx, domain.ItemQuery{})
+	expectNoError(t, "GetAll", err)
+	if len(page.Items) != 3 || page.HasMore || page.NextCursor != "" {
+		t.Errorf("GetAll returned %d items, has_more %v", len(page.Items), page.HasMore)
+	}
+
+	page, err = repo.GetByUserID(ctx, bob, domain.ItemQuery{})
+	expectNoError(t, "GetByUserID", err)
+	if len(page.Items) != 1 || page.Items[0].UserID != bob {
+		t.Errorf("GetByUserID returned %+v", page.Items)
+	}
+
+	page, err = repo.GetByUserID(ctx, "nobody", domain.ItemQuery{})
+	expectNoError(t, "GetByUserID", err)
+	if page.Items == nil || len(page.Items) != 0 {
+		t.Errorf("expected an empty, non-nil page, got %#v", page.Items)
+	}
+
+	_, err = repo.GetAll(ctx, domain.ItemQuery{Limit: domain.MaxPageLimit + 1})
+	expectError(t, "GetAll above the page limit", err, domain.ErrInvalidQuery)
+	_, err = repo.GetAll(ctx, domain.ItemQuery{Cursor: "not a cursor"})
+	expectError(t, "GetAll with a malformed cursor", err, domain.ErrInvalidCursor)
+}
+
+func testPaginate(t *testing.T, repo ports.ItemRepository) {
+	ctx := context.Background()
+	// Repeated titles check that ties are broken by ID.
+	titles := []string{"delta", "alpha", "charlie", "alpha", "bravo", "echo", "charlie"}
+	for _, title := range titles {
+		create(t, repo, alice, title)
+	}
+
+	for _, sortBy := range []domain.SortField{domain.SortByID, domain.SortByCreatedAt, domain.SortByUpdatedAt, domain.SortByTitle} {
+		for _, sortDir := range []domain.SortDirection{domain.SortAsc, domain.SortDesc} {
+			t.Run(fmt.Sprintf("%s_%s", sortBy, sortDir), func(t *testing.T) {
+				all, err := repo.GetAll(ctx, domain.ItemQuery{Limit: domain.MaxPageLimit, SortBy: sortBy, SortDir: sortDir})
+				expectNoError(t, "GetAll", err)
+				if len(all.Items) != len(titles) {
+					t.Fatalf("GetAll returned %d items, want %d", len(all.Items), len(titles))
+				}
+				for i := 1; i < len(all.Items); i++ {
+					if !ordered(&all.Items[i-1], &all.Items[i], sortBy, sortDir) {
+						t.Fatalf("items %d and %d are out of order", all.Items[i-1].ID, all.Items[i].ID)
+					}
+				}
+
+				var paged []domain.Item
+				query := domain.ItemQuery{Limit: 3, SortBy: sortBy, SortDir: sortDir}
+				for pages := 0; ; pages++ {
+					if pages > len(titles) {
+						t.Fatal("pagination does not terminate")
+					}
+					page, err := repo.GetAll(ctx, query)
+					expectNoError(t, "GetAll", err)
+					paged = append(paged, page.Items...)
+					if !page.HasMore {
+						if page.NextCursor != "" {
+							t.Errorf("last page has a cursor")
+						}
+						break
+					}
+					query.Cursor = page.NextCursor
+				}
+
+				if len(paged) != len(all.Items) {
+					t.Fatalf("pages returned %d items, want %d", len(paged), len(all.Items))
+				}
+				for i := range paged {
+					if paged[i].ID != all.Items[i].ID {
+						t.Fatalf("page item %d is %d, want %d", i, paged[i].ID, all.Items[i].ID)
+					}
+				}
+
+				cursor := domain.NewCursor(&all.Items[0], sortBy, sortDir).Encode()
+				other := domain.SortAsc
+				if sortDir == domain.SortAsc {
+					other = domain.SortDesc
+				}
+				_, err = repo.GetAll(ctx, domain.ItemQuery{Cursor: cursor, SortBy: sortBy, SortDir: other})
+				expectError(t, "GetAll with a cursor of another ordering", err, domain.ErrInvalidCursor)
+			})
+		}
+	}
+}
+
+// ordered reports whether a may precede b in a listing.
+func ordered(a, b *domain.Item, sortBy domain.SortField, sortDir domain.SortDirection) bool {
+	var c int
+	switch sortBy {
+	case domain.SortByCreatedAt:
+		c = a.CreatedAt.Compare(b.CreatedAt)
+	case domain.SortByUpdatedAt:
+		c = a.UpdatedAt.Compare(b.UpdatedAt)
+	case domain.SortByTitle:
+		c = cmp.Compare(*a.Title, *b.Title)
+	}
+	if c == 0 {
+		c = cmp.Compare(a.ID, b.ID)
+	}
+	if sortDir == domain.SortDesc {
+		c = -c
+	}
+	return c < 0
+}
+
+func testFilter(t *testing.T, repo ports.ItemRepository) {
+	ctx := context.Background()
+	create(t, repo, alice, "report 2024")
+	create(t, repo, alice, "report_final")
+	create(t, repo, alice, "reportXfinal")
+	create(t, repo, bob, "report 2025")
+	create(t, repo, alice, "100% done")
+	create(t, repo, alice, "Report 2023")
+
+	count := func(filter domain.ItemFilter) int {
+		t.Helper()
+		page, err := repo.GetAll(ctx, domain.ItemQuery{ItemFilter: filter, Limit: domain.MaxPageLimit})
+		expectNoError(t, "GetAll", err)
+		return len(page.Items)
+	}
+
+	// Prefixes are matched case-sensitively.
+	if n := count(domain.ItemFilter{TitlePrefix: "report"}); n != 4 {
+		t.Errorf("title prefix matched %d items, want 4", n)
+	}
+	// LIKE wildcards in the prefix are matched literally.
+	if n := count(domain.ItemFilter{TitlePrefix: "report_"}); n != 1 {
+		t.Errorf("title prefix with _ matched %d items, want 1", n)
+	}
+	if n := count(domain.ItemFilter{TitlePrefix: "100%"}); n != 1 {
+		t.Errorf("title prefix with %% matched %d items, want 1", n)
+	}
+	if n := count(domain.ItemFilter{UserID: bob, TitlePrefix: "report"}); n != 1 {
+		t.Errorf("user and title prefix matched %d items, want 1", n)
+	}
+
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	if n := count(domain.ItemFilter{CreatedAfter: &future}); n != 0 {
+		t.Errorf("created after a future time matched %d items", n)
+	}
+	if n := count(domain.ItemFilter{CreatedAfter: &past, CreatedBefore: &future}); n != 6 {
+		t.Errorf("created in the last hour matched %d items, want 6", n)
+	}
+	// Bounds in another zone denote the same instants.
+	east := time.FixedZone("UTC+7", 7*60*60)
+	recent := time.Now().Add(-time.Minute).In(east)
+	if n := count(domain.ItemFilter{CreatedAfter: &recent}); n != 6 {
+		t.Errorf("created after a bound in UTC+7 matched %d items, want 6", n)
+	}
+	if n := count(domain.ItemFilter{UpdatedBefore: &past}); n != 0 {
+		t.Errorf("updated before an hour ago matched %d items", n)
+	}
+	if n := count(domain.ItemFilter{UpdatedAfter: &past}); n != 6 {
+		t.Errorf("updated in the last hour matched %d items, want 6", n)
+	}
+}
+
+func testDoneContext(t *testing.T, repo ports.ItemRepository) {
+	item := create(t, repo, alice, "unreachable")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.GetByID(ctx, alice, item.ID)
+	expectError(t, "GetByID with a cancelled context", err, context.Canceled)
+	expectError(t, "Create with a cancelled context", repo.Create(ctx, &domain.Item{UserID: alice, Title: ptr("late")}), context.Canceled)
+	_, err = repo.GetAll(ctx, domain.ItemQuery{})
+	expectError(t, "GetAll with a cancelled context", err, context.Canceled)
+}
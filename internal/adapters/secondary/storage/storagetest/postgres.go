@@ -0,0 +1,87 @@
+package storagetest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	// Registers the "pgx" database/sql driver.
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/logger"
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/storage/migrations"
+)
+
+// PostgresEnv names the environment variable holding the DSN of the
+// PostgreSQL database the adapters' tests and benchmarks run against. They
+// are skipped when it is unset.
+const PostgresEnv = "TEST_DATABASE_URL"
+
+// PostgresDSN returns the DSN of PostgresEnv with its connections confined
+// to a fresh schema, migrated to the latest version and dropped once tb
+// ends. It skips tb when PostgresEnv is unset.
+func PostgresDSN(tb testing.TB) string {
+	tb.Helper()
+	dsn := os.Getenv(PostgresEnv)
+	if dsn == "" {
+		tb.Skipf("%s is not set", PostgresEnv)
+	}
+	ctx := context.Background()
+
+	admin, err := sql.Open("pgx", dsn)
+	if err != nil {
+		tb.Fatalf("open %s: %v", PostgresEnv, err)
+	}
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := admin.ExecContext(ctx, "CREATE SCHEMA "+schema); err != nil {
+		admin.Close()
+		tb.Fatalf("create schema: %v", err)
+	}
+	tb.Cleanup(func() {
+		if _, err := admin.ExecContext(context.Background(), "DROP SCHEMA "+schema+" CASCADE"); err != nil {
+			tb.Errorf("drop schema %s: %v", schema, err)
+		}
+		admin.Close()
+	})
+
+	schemaDSN, err := withSearchPath(dsn, schema)
+	if err != nil {
+		tb.Fatalf("parse %s: %v", PostgresEnv, err)
+	}
+	db, err := sql.Open("pgx", schemaDSN)
+	if err != nil {
+		tb.Fatalf("open schema: %v", err)
+	}
+	defer db.Close()
+	migrator, err := migrations.New(db, migrations.Postgres, logger.New(logger.WithOutput(io.Discard)))
+	if err != nil {
+		tb.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		tb.Fatalf("apply migrations: %v", err)
+	}
+	return schemaDSN
+}
+
+// withSearchPath returns dsn with its connections using schema. Both URL and
+// keyword/value DSNs are accepted; unknown parameters are sent to the server
+// as run-time settings.
+func withSearchPath(dsn, schema string) (string, error) {
+	if !strings.HasPrefix(dsn, "postgres://") && !strings.HasPrefix(dsn, "postgresql://") {
+		return dsn + " search_path=" + schema, nil
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,206 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+// TxFactory returns an empty repository for one test along with the
+// ports.TxManager of its store. It registers any cleanup with t.Cleanup.
+type TxFactory func(t *testing.T) (ports.ItemRepository, ports.TxManager)
+
+// RunTxManagerContract runs the transaction contract against the
+// repositories and managers returned by newStore:
+//
+//	func TestTxContract(t *testing.T) {
+//		storagetest.RunTxManagerContract(t, func(t *testing.T) (ports.ItemRepository, ports.TxManager) {
+//			repo := memory.NewMemoryItemRepository()
+//			return repo, memory.NewTxManager(repo)
+//		})
+//	}
+func RunTxManagerContract(t *testing.T, newStore TxFactory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, repo ports.ItemRepository, tm ports.TxManager)
+	}{
+		{"Commit", testTxCommit},
+		{"Rollback", testTxRollback},
+		{"Nested", testTxNested},
+		{"BatchInTx", testTxBatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, tm := newStore(t)
+			tt.run(t, repo, tm)
+		})
+	}
+}
+
+var errAbort = errors.New("abort")
+
+func testTxCommit(t *testing.T, repo ports.ItemRepository, tm ports.TxManager) {
+	ctx := context.Background()
+	existing := create(t, repo, alice, "existing")
+
+	var created domain.Item
+	err := tm.WithinTx(ctx, func(ctx context.Context) error {
+		created = domain.Item{UserID: alice, Title: ptr("created")}
+		if err := repo.Create(ctx, &created); err != nil {
+			return err
+		}
+		updated := *existing
+		updated.Title = ptr("updated")
+		if err := repo.Update(ctx, &updated, []string{domain.ItemFieldTitle}); err != nil {
+			return err
+		}
+		// The transaction reads its own writes.
+		got, err := repo.GetByID(ctx, alice, existing.ID)
+		if err != nil {
+			return err
+		}
+		if *got.Title != "updated" {
+			t.Errorf("GetByID in the transaction returned title %q, want the update", *got.Title)
+		}
+		return nil
+	})
+	expectNoError(t, "WithinTx", err)
+
+	_, err = repo.GetByID(ctx, alice, created.ID)
+	expectNoError(t, "GetByID of the created item", err)
+	got, err := repo.GetByID(ctx, alice, existing.ID)
+	expectNoError(t, "GetByID of the updated item", err)
+	if *got.Title != "updated" || got.Version != existing.Version+1 {
+		t.Errorf("committed item has title %q at version %d", *got.Title, got.Version)
+	}
+}
+
+func testTxRollback(t *testing.T, repo ports.ItemRepository, tm ports.TxManager) {
+	ctx := context.Background()
+	updated := create(t, repo, alice, "updated")
+	deleted := create(t, repo, alice, "deleted")
+
+	var created domain.Item
+	err := tm.WithinTx(ctx, func(ctx context.Context) error {
+		created = domain.Item{UserID: alice, Title: ptr("created")}
+		if err := repo.Create(ctx, &created); err != nil {
+			return err
+		}
+		item := *updated
+		item.Title = ptr("lost")
+		if err := repo.Update(ctx, &item, []string{domain.ItemFieldTitle}); err != nil {
+			return err
+		}
+		if err := repo.Delete(ctx, alice, deleted.ID, 0); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	expectError(t, "WithinTx", err, errAbort)
+
+	_, err = repo.GetByID(ctx, alice, created.ID)
+	expectError(t, "GetByID of an item created by a rolled back transaction", err, domain.ErrItemNotFound)
+	got, err := repo.GetByID(ctx, alice, updated.ID)
+	expectNoError(t, "GetByID of the updated item", err)
+	if *got.Title != "updated" || got.Version != updated.Version {
+		t.Errorf("rolled back update left title %q at version %d", *got.Title, got.Version)
+	}
+	_, err = repo.GetByID(ctx, alice, deleted.ID)
+	expectNoError(t, "GetByID of the deleted item", err)
+}
+
+func testTxNested(t *testing.T, repo ports.ItemRepository, tm ports.TxManager) {
+	ctx := context.Background()
+
+	// An inner call joins the outer transaction, which decides the outcome.
+	var created domain.Item
+	err := tm.WithinTx(ctx, func(ctx context.Context) error {
+		err := tm.WithinTx(ctx, func(ctx context.Context) error {
+			created = domain.Item{UserID: alice, Title: ptr("inner")}
+			return repo.Create(ctx, &created)
+		})
+		if err != nil {
+			return err
+		}
+		return errAbort
+	})
+	expectError(t, "WithinTx", err, errAbort)
+	_, err = repo.GetByID(ctx, alice, created.ID)
+	expectError(t, "GetByID of an item created by a joined transaction", err, domain.ErrItemNotFound)
+
+	err = tm.WithinTx(ctx, func(ctx context.Context) error {
+		return tm.WithinTx(ctx, func(context.Context) error {
+			return errAbort
+		})
+	})
+	expectError(t, "WithinTx with a failing inner call", err, errAbort)
+}
+
+func testTxBatch(t *testing.T, repo ports.ItemRepository, tm ports.TxManager) {
+	ctx := context.Background()
+	single := create(t, repo, alice, "single")
+	batched := create(t, repo, alice, "batched")
+
+	// A failed batch undoes its own writes only; the transaction goes on.
+	err := tm.WithinTx(ctx, func(ctx context.Context) error {
+		item := *single
+		item.Title = ptr("kept")
+		if err := repo.Update(ctx, &item, []string{domain.ItemFieldTitle}); err != nil {
+			return err
+		}
+		fresh, stale := *batched, *batched
+		fresh.Title = ptr("lost")
+		stale.ID = single.ID
+		err := repo.UpdateMany(ctx, []*domain.Item{&fresh, &stale}, []string{domain.ItemFieldTitle})
+		expectBatchError(t, "UpdateMany with a stale item", err, 1, domain.ErrVersionMismatch)
+		return nil
+	})
+	expectNoError(t, "WithinTx", err)
+
+	got, err := repo.GetByID(ctx, alice, single.ID)
+	expectNoError(t, "GetByID", err)
+	if *got.Title != "kept" {
+		t.Errorf("update before a failed batch left title %q", *got.Title)
+	}
+	got, err = repo.GetByID(ctx, alice, batched.ID)
+	expectNoError(t, "GetByID", err)
+	if *got.Title != "batched" || got.Version != batched.Version {
+		t.Errorf("failed batch left title %q at version %d", *got.Title, got.Version)
+	}
+}
+
+// RunTxConflictContract checks that a transaction writing an item changed
+// since it first read it fails with domain.ErrTxConflict. It suits stores
+// whose transactions run at repeatable read or above and fail, rather than
+// wait for, concurrent changes; newStore should return such a manager.
+func RunTxConflictContract(t *testing.T, newStore TxFactory) {
+	t.Run("Conflict", func(t *testing.T) {
+		repo, tm := newStore(t)
+		testTxConflict(t, repo, tm)
+	})
+}
+
+func testTxConflict(t *testing.T, repo ports.ItemRepository, tm ports.TxManager) {
+	ctx := context.Background()
+	item := create(t, repo, alice, "contended")
+	fields := []string{domain.ItemFieldTitle}
+
+	err := tm.WithinTx(ctx, func(txCtx context.Context) error {
+		if _, err := repo.GetByID(txCtx, alice, item.ID); err != nil {
+			return err
+		}
+		// Another transaction changes the item once this one has read it.
+		other := *item
+		other.Title = ptr("concurrent")
+		expectNoError(t, "concurrent Update", repo.Update(ctx, &other, fields))
+
+		mine := *item
+		mine.Title = ptr("conflicting")
+		return repo.Update(txCtx, &mine, fields)
+	})
+	expectError(t, "WithinTx", err, domain.ErrTxConflict)
+	expectError(t, "WithinTx", err, domain.ErrUnavailable)
+}
@@ -0,0 +1,107 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Supported span exporters.
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+// Config holds the settings of the tracer provider.
+type Config struct {
+	// Exporter is one of none, otlp, stdout or file.
+	Exporter string
+	// Endpoint is the OTLP/HTTP collector, either host:port or a URL.
+	Endpoint string
+	// Insecure disables TLS towards the collector.
+	Insecure bool
+	// File receives the spans, one JSON document each, with the file exporter.
+	File        string
+	ServiceName string
+	// SampleRatio is the fraction of new traces recorded. Traces started by
+	// an upstream service follow its sampling decision.
+	SampleRatio float64
+}
+
+// ShutdownFunc flushes buffered spans and releases the exporter.
+type ShutdownFunc func(ctx context.Context) error
+
+// NewProvider builds the tracer provider described by cfg. With the none
+// exporter spans are not recorded at all.
+func NewProvider(ctx context.Context, cfg Config) (trace.TracerProvider, ShutdownFunc, error) {
+	var (
+		exporter sdktrace.SpanExporter
+		closer   func() error
+		err      error
+	)
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return noop.NewTracerProvider(), func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		exporter, err = otlpExporter(ctx, cfg)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterFile:
+		if cfg.File == "" {
+			return nil, nil, errors.New("tracing: file exporter requires a file")
+		}
+		var f *os.File
+		f, err = os.OpenFile(cfg.File, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		if err == nil {
+			closer = f.Close
+			exporter, err = stdouttrace.New(stdouttrace.WithWriter(f))
+		}
+	default:
+		return nil, nil, fmt.Errorf("tracing: unknown exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("tracing: failed to create %s exporter: %w", cfg.Exporter, err)
+	}
+
+	res := resource.NewSchemaless(attribute.String("service.name", cfg.ServiceName))
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+
+	shutdown := func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			err = errors.Join(err, closer())
+		}
+		return err
+	}
+	return provider, shutdown, nil
+}
+
+func otlpExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	var opts []otlptracehttp.Option
+	switch {
+	case strings.Contains(cfg.Endpoint, "://"):
+		opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	case cfg.Endpoint != "":
+		opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	return otlptracehttp.New(ctx, opts...)
+}
@@ -0,0 +1,59 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewProvider(t *testing.T) {
+	tests := []struct {
+		name        string
+		sampleRatio float64
+		wantSpan    bool
+	}{
+		{"sampled", 1, true},
+		{"not sampled", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "spans.json")
+			provider, shutdown, err := NewProvider(context.Background(), Config{
+				Exporter:    ExporterFile,
+				File:        file,
+				ServiceName: "item-service",
+				SampleRatio: tt.sampleRatio,
+			})
+			if err != nil {
+				t.Fatalf("NewProvider: %v", err)
+			}
+			_, span := NewTracer(provider, "test").Start(context.Background(), "ItemService.create_item")
+			span.SetAttribute("outcome", "success")
+			span.End()
+			if err := shutdown(context.Background()); err != nil {
+				t.Fatalf("shutdown: %v", err)
+			}
+
+			raw, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("read spans: %v", err)
+			}
+			if got := strings.Contains(string(raw), "ItemService.create_item"); got != tt.wantSpan {
+				t.Errorf("span exported: %v, want %v", got, tt.wantSpan)
+			}
+			if tt.wantSpan && !strings.Contains(string(raw), "item-service") {
+				t.Error("exported span lacks the service name")
+			}
+		})
+	}
+}
+
+func TestNewProviderRejectsConfig(t *testing.T) {
+	for _, cfg := range []Config{{Exporter: "zipkin"}, {Exporter: ExporterFile}} {
+		if _, _, err := NewProvider(context.Background(), cfg); err == nil {
+			t.Errorf("NewProvider accepted %+v", cfg)
+		}
+	}
+}
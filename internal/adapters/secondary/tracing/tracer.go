@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+// otelTracer implements ports.Tracer with OpenTelemetry.
+type otelTracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer returns a ports.Tracer creating spans with the named tracer of provider.
+func NewTracer(provider trace.TracerProvider, name string) ports.Tracer {
+	return &otelTracer{tracer: provider.Tracer(name)}
+}
+
+func (t *otelTracer) Start(ctx context.Context, name string) (context.Context, ports.Span) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindInternal))
+	return ctx, &otelSpan{span: span}
+}
+
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s *otelSpan) SetAttribute(key string, value any) {
+	switch v := value.(type) {
+	case string:
+		s.span.SetAttributes(attribute.String(key, v))
+	case bool:
+		s.span.SetAttributes(attribute.Bool(key, v))
+	case int:
+		s.span.SetAttributes(attribute.Int(key, v))
+	case int64:
+		s.span.SetAttributes(attribute.Int64(key, v))
+	default:
+		s.span.SetAttributes(attribute.String(key, fmt.Sprint(v)))
+	}
+}
+
+func (s *otelSpan) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+func (s *otelSpan) End() {
+	s.span.End()
+}
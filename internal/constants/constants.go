@@ -3,7 +3,6 @@ package constants
 // Common HTTP headers
 const (
 	HeaderCorrelationID = "X-Correlation-ID"
-	HeaderUserID        = "X-User-ID"
 )
 
 // Context keys
@@ -14,6 +13,8 @@ const (
 	ContextCorrelationID contextKey = "correlation_id"
 	// ContextKeyLogger is the key used to store the logger in the context
 	ContextKeyLogger contextKey = "logger"
+	// ContextKeyUser is the key used to store the authenticated user in the context
+	ContextKeyUser contextKey = "user"
 )
 
 // Error messages
@@ -0,0 +1,15 @@
+package domain
+
+import "errors"
+
+// Storage level failures shared by every entity. Repositories translate
+// driver specific errors into these so callers never depend on the driver.
+var (
+	ErrInvalidReference = errors.New("referenced resource does not exist")
+	ErrUnavailable      = errors.New("service temporarily unavailable")
+	ErrTimeout          = errors.New("operation timed out")
+	// ErrTxConflict accompanies ErrUnavailable when a transaction lost to a
+	// concurrent one, through a serialization failure or a deadlock. The
+	// transaction can be retried from the start.
+	ErrTxConflict = errors.New("transaction conflict")
+)
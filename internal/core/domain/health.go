@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// HealthStatus is the state of the service or one of its dependencies.
+type HealthStatus string
+
+const (
+	HealthStatusUp   HealthStatus = "up"
+	HealthStatusDown HealthStatus = "down"
+)
+
+// HealthCheck is the outcome of checking a single dependency.
+type HealthCheck struct {
+	Name      string       `json:"name"`
+	Status    HealthStatus `json:"status"`
+	LatencyMs int64        `json:"latency_ms"`
+	Error     string       `json:"error,omitempty"`
+	CheckedAt time.Time    `json:"checked_at"`
+}
+
+// HealthReport aggregates the dependency checks. The service is up only when
+// every check is up.
+type HealthReport struct {
+	Status HealthStatus  `json:"status"`
+	Checks []HealthCheck `json:"checks,omitempty"`
+}
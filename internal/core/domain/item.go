@@ -2,28 +2,109 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
+// Item field names as they appear in JSON payloads.
+const (
+	ItemFieldID          = "id"
+	ItemFieldTitle       = "title"
+	ItemFieldDescription = "description"
+	ItemFieldCreatedAt   = "created_at"
+	ItemFieldUpdatedAt   = "updated_at"
+	ItemFieldDeletedAt   = "deleted_at"
+	ItemFieldUserID      = "user_id"
+	ItemFieldVersion     = "version"
+)
+
+// MutableItemFields lists the fields clients may change. Every other field
+// is managed by the service.
+var MutableItemFields = []string{ItemFieldTitle, ItemFieldDescription}
+
+var itemFields = []string{
+	ItemFieldID, ItemFieldTitle, ItemFieldDescription, ItemFieldCreatedAt,
+	ItemFieldUpdatedAt, ItemFieldDeletedAt, ItemFieldUserID, ItemFieldVersion,
+}
+
+// IsItemField reports whether name is a field of the item JSON representation.
+func IsItemField(name string) bool {
+	return slices.Contains(itemFields, name)
+}
+
+// Length limits of item fields, counted in characters.
+const (
+	MaxTitleLength       = 255
+	MaxDescriptionLength = 4000
+)
+
+// Item is a user owned entry. Version is incremented on every change and
+// backs optimistic concurrency control.
 type Item struct {
-	ID          int64     `json:"id" gorm:"primaryKey;autoIncrement"`
-	Title       *string   `json:"title" gorm:"not null"`
-	Description *string   `json:"description"`
-	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
-	DeletedAt   time.Time `json:"deleted_at" gorm:"index"`
-	UserID      string    `json:"user_id" gorm:"not null;index"`
+	ID          int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Title       *string    `json:"title" gorm:"not null"`
+	Description *string    `json:"description"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" gorm:"index"`
+	UserID      string     `json:"user_id" gorm:"not null;index"`
+	Version     int64      `json:"version" gorm:"not null;default:1"`
 }
 
 var (
 	ErrItemNotFound = errors.New("item not found")
-	ErrItemExists   = errors.New("item already exists")
-	ErrInvalidItem  = errors.New("invalid item")
+	// ErrItemNotOwned is returned for an item of another user.
+	ErrItemNotOwned    = errors.New("item belongs to another user")
+	ErrItemExists      = errors.New("item already exists")
+	ErrInvalidItem     = errors.New("invalid item")
+	ErrVersionMismatch = errors.New("item version mismatch")
 )
 
+// IsDeleted reports whether the item is in the trash.
+func (i *Item) IsDeleted() bool {
+	return i.DeletedAt != nil
+}
+
+// Validate checks every field and returns a *ValidationError listing all
+// violations, or nil when the item is valid.
 func (i *Item) Validate() error {
+	var verr ValidationError
+
 	if i.Title == nil || *i.Title == "" {
-		return ErrInvalidItem
+		verr.Add(ItemFieldTitle, CodeRequired, "title is required")
+	} else {
+		validateText(&verr, ItemFieldTitle, *i.Title, MaxTitleLength, false)
+	}
+
+	if i.Description != nil {
+		validateText(&verr, ItemFieldDescription, *i.Description, MaxDescriptionLength, true)
+	}
+
+	return verr.Err()
+}
+
+// validateText checks the length and characters of a free text field.
+// Multiline fields may contain tabs and line breaks.
+func validateText(verr *ValidationError, field, value string, maxLen int, multiline bool) {
+	if !utf8.ValidString(value) {
+		verr.Add(field, CodeInvalidCharacters, field+" must be valid UTF-8")
+		return
+	}
+
+	if n := utf8.RuneCountInString(value); n > maxLen {
+		verr.Add(field, CodeTooLong, fmt.Sprintf("%s must be at most %d characters, got %d", field, maxLen, n))
+	}
+
+	for _, r := range value {
+		if multiline && (r == '\n' || r == '\r' || r == '\t') {
+			continue
+		}
+		if unicode.IsControl(r) {
+			verr.Add(field, CodeInvalidCharacters, field+" must not contain control characters")
+			return
+		}
 	}
-	return nil
 }
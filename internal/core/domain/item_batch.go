@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultMaxBatchSize is the number of items a batch may hold unless
+// configured otherwise.
+const DefaultMaxBatchSize = 100
+
+// BatchMode selects how a batch treats items that cannot be written.
+type BatchMode string
+
+const (
+	// BatchAtomic writes every item of the batch or, when one fails, none.
+	BatchAtomic BatchMode = "atomic"
+	// BatchPartial writes every item that can be written and reports the
+	// others. Items are written one by one, outside of a transaction, so
+	// others may see the batch half applied and an interrupted batch keeps
+	// the items written so far.
+	BatchPartial BatchMode = "partial"
+)
+
+var (
+	ErrInvalidBatch  = errors.New("invalid batch")
+	ErrBatchTooLarge = errors.New("batch too large")
+	// ErrBatchAborted is reported for the items of an atomic batch that
+	// were not written because another item failed.
+	ErrBatchAborted = errors.New("batch aborted")
+)
+
+// ItemRef identifies an item of a batch delete. A non-zero Version must
+// match the stored version.
+type ItemRef struct {
+	ID      int64 `json:"id"`
+	Version int64 `json:"version,omitempty"`
+}
+
+// BatchResult is the outcome of one item of a batch: the stored item, or
+// the error it failed with.
+type BatchResult struct {
+	Item *Item
+	Err  error
+}
+
+// BatchItemError reports the item of a batch a write failed on.
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("batch item %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "errors"
+
+// PatchType identifies the format of a partial update document.
+type PatchType string
+
+const (
+	// PatchTypeMerge is a JSON Merge Patch document (RFC 7396).
+	PatchTypeMerge PatchType = "application/merge-patch+json"
+	// PatchTypeJSON is a JSON Patch document (RFC 6902).
+	PatchTypeJSON PatchType = "application/json-patch+json"
+)
+
+var (
+	ErrInvalidPatch  = errors.New("invalid patch document")
+	ErrPatchConflict = errors.New("patch cannot be applied to the current item")
+)
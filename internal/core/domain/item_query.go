@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Pagination limits applied when listing items.
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// SortField identifies the item attribute used to order a listing.
+type SortField string
+
+const (
+	SortByID        SortField = "id"
+	SortByCreatedAt SortField = "created_at"
+	SortByUpdatedAt SortField = "updated_at"
+	SortByTitle     SortField = "title"
+)
+
+// SortDirection is the ordering direction of a listing.
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
+var (
+	ErrInvalidQuery  = errors.New("invalid query")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// ItemFilter narrows down the set of items returned by a listing.
+// Zero values mean "no constraint".
+type ItemFilter struct {
+	UserID        string
+	TitlePrefix   string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+}
+
+// ItemQuery describes a single page request over items.
+type ItemQuery struct {
+	ItemFilter
+	Limit   int
+	Cursor  string
+	SortBy  SortField
+	SortDir SortDirection
+}
+
+// ItemPage is one page of a listing together with the cursor of the next page.
+type ItemPage struct {
+	Items      []Item `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// Normalize fills in defaults and validates the query.
+func (q *ItemQuery) Normalize() error {
+	if q.Limit == 0 {
+		q.Limit = DefaultPageLimit
+	}
+	if q.Limit < 0 || q.Limit > MaxPageLimit {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidQuery, MaxPageLimit)
+	}
+
+	switch q.SortBy {
+	case "":
+		q.SortBy = SortByCreatedAt
+	case SortByID, SortByCreatedAt, SortByUpdatedAt, SortByTitle:
+	default:
+		return fmt.Errorf("%w: sort must be one of %s, %s, %s or %s",
+			ErrInvalidQuery, SortByID, SortByCreatedAt, SortByUpdatedAt, SortByTitle)
+	}
+
+	switch q.SortDir {
+	case "":
+		q.SortDir = SortDesc
+	case SortAsc, SortDesc:
+	default:
+		return fmt.Errorf("%w: order must be %s or %s", ErrInvalidQuery, SortAsc, SortDesc)
+	}
+
+	if q.CreatedAfter != nil && q.CreatedBefore != nil && !q.CreatedAfter.Before(*q.CreatedBefore) {
+		return fmt.Errorf("%w: created_after must be before created_before", ErrInvalidQuery)
+	}
+	if q.UpdatedAfter != nil && q.UpdatedBefore != nil && !q.UpdatedAfter.Before(*q.UpdatedBefore) {
+		return fmt.Errorf("%w: updated_after must be before updated_before", ErrInvalidQuery)
+	}
+
+	return nil
+}
+
+// Cursor is the decoded form of the opaque pagination token. It records the
+// sort key of the last item on a page so the next page can resume after it.
+type Cursor struct {
+	SortBy  SortField     `json:"s"`
+	SortDir SortDirection `json:"d"`
+	ID      int64         `json:"i"`
+	Time    *time.Time    `json:"t,omitempty"`
+	Title   *string       `json:"v,omitempty"`
+}
+
+// NewCursor builds the cursor pointing right after item for the given ordering.
+func NewCursor(item *Item, sortBy SortField, sortDir SortDirection) Cursor {
+	c := Cursor{SortBy: sortBy, SortDir: sortDir, ID: item.ID}
+	switch sortBy {
+	case SortByCreatedAt:
+		t := item.CreatedAt
+		c.Time = &t
+	case SortByUpdatedAt:
+		t := item.UpdatedAt
+		c.Time = &t
+	case SortByTitle:
+		title := ""
+		if item.Title != nil {
+			title = *item.Title
+		}
+		c.Title = &title
+	}
+	return c
+}
+
+// Encode returns the opaque, URL-safe representation of the cursor.
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor parses a cursor token and checks that it was issued for the
+// same ordering as the query it is used with.
+func DecodeCursor(token string, sortBy SortField, sortDir SortDirection) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if c.SortBy != sortBy || c.SortDir != sortDir {
+		return nil, ErrInvalidCursor
+	}
+
+	switch sortBy {
+	case SortByCreatedAt, SortByUpdatedAt:
+		if c.Time == nil {
+			return nil, ErrInvalidCursor
+		}
+	case SortByTitle:
+		if c.Title == nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestItemValidate(t *testing.T) {
+	text := func(s string) *string { return &s }
+	tests := []struct {
+		name string
+		item Item
+		want []FieldError
+	}{
+		{"valid", Item{Title: text("title"), Description: text("line\n\tindented")}, nil},
+		{"missing title", Item{}, []FieldError{
+			{ItemFieldTitle, CodeRequired, "title is required"},
+		}},
+		{"empty title", Item{Title: text("")}, []FieldError{
+			{ItemFieldTitle, CodeRequired, "title is required"},
+		}},
+		{"every violation at once", Item{Title: text(strings.Repeat("é", MaxTitleLength+1)), Description: text("bell\a")}, []FieldError{
+			{ItemFieldTitle, CodeTooLong, "title must be at most 255 characters, got 256"},
+			{ItemFieldDescription, CodeInvalidCharacters, "description must not contain control characters"},
+		}},
+		{"line break in title", Item{Title: text("two\nlines")}, []FieldError{
+			{ItemFieldTitle, CodeInvalidCharacters, "title must not contain control characters"},
+		}},
+		{"invalid UTF-8", Item{Title: text("t"), Description: text("\xff")}, []FieldError{
+			{ItemFieldDescription, CodeInvalidCharacters, "description must be valid UTF-8"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.item.Validate()
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Validate returned %v", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) || !errors.Is(err, ErrInvalidItem) {
+				t.Fatalf("Validate returned %v, want a *ValidationError matching ErrInvalidItem", err)
+			}
+			if !reflect.DeepEqual(verr.Errors, tt.want) {
+				t.Errorf("Validate reported %+v, want %+v", verr.Errors, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Bounds of a temporary log level change.
+const (
+	DefaultLogLevelDuration = 15 * time.Minute
+	MaxLogLevelDuration     = 24 * time.Hour
+)
+
+var ErrInvalidLogLevel = errors.New("invalid log level")
+
+// LogLevelStatus describes the level the application logs at.
+type LogLevelStatus struct {
+	// Level is the level in effect.
+	Level string `json:"level"`
+	// Configured is the level from the configuration, restored when a
+	// temporary change expires.
+	Configured string `json:"configured"`
+	// ExpiresAt is set while a temporary change is in effect.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
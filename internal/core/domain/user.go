@@ -1,10 +1,50 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Well-known roles and scopes granted to users through token claims.
+const (
+	RoleAdmin = "admin"
+
+	// ScopeItemsAdmin grants the same item privileges as RoleAdmin.
+	ScopeItemsAdmin = "items:admin"
+)
 
 type User struct {
 	ID        string    `json:"user_id"`
+	Roles     []string  `json:"roles,omitempty"`
+	Scopes    []string  `json:"scopes,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	DeletedAt time.Time `json:"deleted_at"`
 }
+
+var (
+	ErrUnauthenticated = errors.New("unauthenticated")
+	ErrInvalidToken    = errors.New("invalid token")
+	ErrForbidden       = errors.New("forbidden")
+)
+
+// HasRole reports whether the user was granted role.
+func (u *User) HasRole(role string) bool {
+	return slices.Contains(u.Roles, role)
+}
+
+// HasScope reports whether the user was granted scope.
+func (u *User) HasScope(scope string) bool {
+	return slices.Contains(u.Scopes, scope)
+}
+
+// IsAdmin reports whether the user may act on items of every user.
+func (u *User) IsAdmin() bool {
+	return u.HasRole(RoleAdmin) || u.HasScope(ScopeItemsAdmin)
+}
+
+// CanAccessUser reports whether the user may read or manage the items owned by userID.
+func (u *User) CanAccessUser(userID string) bool {
+	return u.ID == userID || u.IsAdmin()
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Validation error codes reported in FieldError.Code.
+const (
+	CodeRequired          = "required"
+	CodeTooLong           = "too_long"
+	CodeInvalidCharacters = "invalid_characters"
+	CodeInvalidType       = "invalid_type"
+	CodeUnknownField      = "unknown_field"
+	CodeImmutable         = "immutable"
+)
+
+// FieldError describes a single invalid input field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError collects every violation found in a payload. It matches
+// ErrInvalidItem with errors.Is.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Add records a violation of field.
+func (e *ValidationError) Add(field, code, message string) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Err returns e when it holds violations and nil otherwise.
+func (e *ValidationError) Err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		parts[i] = fmt.Sprintf("%s: %s", fe.Field, fe.Message)
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidItem, strings.Join(parts, "; "))
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidItem
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+)
+
+// TokenVerifier validates a bearer token and resolves the user it was issued to.
+type TokenVerifier interface {
+	// Verify checks the token signature and claims. It returns an error
+	// wrapping domain.ErrInvalidToken when the token must be rejected.
+	Verify(ctx context.Context, token string) (*domain.User, error)
+}
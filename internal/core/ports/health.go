@@ -0,0 +1,12 @@
+package ports
+
+import "context"
+
+// HealthChecker probes a dependency the service needs to serve traffic.
+type HealthChecker interface {
+	// Name identifies the dependency in health reports.
+	Name() string
+	// Check returns an error when the dependency is unusable. It must
+	// honour the context deadline.
+	Check(ctx context.Context) error
+}
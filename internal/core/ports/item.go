@@ -2,24 +2,53 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
 )
 
 type ItemService interface {
 	Create(ctx context.Context, item *domain.Item) error
-	Update(ctx context.Context, item *domain.Item) error
-	Delete(ctx context.Context, item *domain.Item) error
-	GetAll(ctx context.Context) ([]domain.Item, error)
+	Replace(ctx context.Context, id, expectedVersion int64, item *domain.Item) error
+	Patch(ctx context.Context, id, expectedVersion int64, patchType domain.PatchType, doc []byte) (*domain.Item, error)
+	Delete(ctx context.Context, id, expectedVersion int64) error
+	CreateBatch(ctx context.Context, items []*domain.Item, mode domain.BatchMode) ([]domain.BatchResult, error)
+	ReplaceBatch(ctx context.Context, items []*domain.Item, mode domain.BatchMode) ([]domain.BatchResult, error)
+	DeleteBatch(ctx context.Context, refs []domain.ItemRef, mode domain.BatchMode) ([]domain.BatchResult, error)
+	Restore(ctx context.Context, id int64) (*domain.Item, error)
+	GetAll(ctx context.Context, query domain.ItemQuery) (*domain.ItemPage, error)
 	GetByID(ctx context.Context, id int64) (*domain.Item, error)
-	GetByUserID(ctx context.Context, userID string) ([]domain.Item, error)
+	GetByUserID(ctx context.Context, userID string, query domain.ItemQuery) (*domain.ItemPage, error)
+	GetDeleted(ctx context.Context, query domain.ItemQuery) (*domain.ItemPage, error)
 }
 
+// ItemRepository persists items. Methods addressing a single item are scoped
+// to its owner and return domain.ErrItemNotFound when the item does not exist
+// or belongs to another user, except GetByID, which returns
+// domain.ErrItemNotOwned for an item of another user. Deleted items are kept in a trash and ignored by
+// every method except Restore, GetDeleted and Purge. Every method stops and
+// returns the context error once ctx is done.
 type ItemRepository interface {
-	Create(item *domain.Item) error
-	Update(item *domain.Item) error
-	Delete(item *domain.Item) error
-	GetAll() ([]domain.Item, error)
-	GetByID(id int64) (*domain.Item, error)
-	GetByUserID(userID string) ([]domain.Item, error)
+	Create(ctx context.Context, item *domain.Item) error
+	// CreateMany stores items at once, as by Create. Either every item is
+	// stored or none is.
+	CreateMany(ctx context.Context, items []*domain.Item) error
+	// Update writes the given mutable fields of item and reloads it. It fails
+	// with domain.ErrVersionMismatch unless the stored version is item.Version.
+	Update(ctx context.Context, item *domain.Item, fields []string) error
+	// UpdateMany updates items as by Update. Either every item is updated or
+	// none is; a failing item is reported as a *domain.BatchItemError.
+	UpdateMany(ctx context.Context, items []*domain.Item, fields []string) error
+	// Delete trashes the item; a non-zero expectedVersion must match.
+	Delete(ctx context.Context, userID string, id int64, expectedVersion int64) error
+	// DeleteMany trashes the user's items as by Delete. Either every item is
+	// trashed or none is; a failing item is reported as a
+	// *domain.BatchItemError.
+	DeleteMany(ctx context.Context, userID string, refs []domain.ItemRef) error
+	Restore(ctx context.Context, userID string, id int64) (*domain.Item, error)
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+	GetAll(ctx context.Context, query domain.ItemQuery) (*domain.ItemPage, error)
+	GetByID(ctx context.Context, userID string, id int64) (*domain.Item, error)
+	GetByUserID(ctx context.Context, userID string, query domain.ItemQuery) (*domain.ItemPage, error)
+	GetDeleted(ctx context.Context, query domain.ItemQuery) (*domain.ItemPage, error)
 }
@@ -59,3 +59,11 @@ func Int64(key string, value int64) Field {
 func Error(err error) Field {
 	return Field{Key: "error", Value: err}
 }
+
+// LogLevelController reads and changes the minimum level of the application logger.
+type LogLevelController interface {
+	// Level returns the name of the current level, e.g. "info".
+	Level() string
+	// SetLevel changes the level; it fails for unknown level names.
+	SetLevel(name string) error
+}
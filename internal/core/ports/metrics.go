@@ -0,0 +1,18 @@
+package ports
+
+import "time"
+
+// Metrics records measurements of the core services.
+type Metrics interface {
+	// ObserveOperation records the outcome and duration of a service operation.
+	ObserveOperation(operation, outcome string, duration time.Duration)
+}
+
+// HTTPMetrics records measurements of the HTTP server.
+type HTTPMetrics interface {
+	// RequestStarted marks a request as in flight.
+	RequestStarted()
+	// RequestFinished records a completed request. route is the route
+	// template, e.g. /api/v1/items/:id, never the raw path.
+	RequestFinished(method, route string, status int, duration time.Duration)
+}
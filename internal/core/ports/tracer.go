@@ -0,0 +1,21 @@
+package ports
+
+import "context"
+
+// Tracer starts spans around units of work.
+type Tracer interface {
+	// Start begins a span named name, as a child of the span carried by ctx
+	// if any, and returns a context carrying the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a timed unit of work within a trace.
+type Span interface {
+	// SetAttribute annotates the span. Values other than strings, booleans
+	// and integers are recorded using their string form.
+	SetAttribute(key string, value any)
+	// RecordError marks the span as failed with err.
+	RecordError(err error)
+	// End completes the span.
+	End()
+}
@@ -0,0 +1,15 @@
+package ports
+
+import "context"
+
+// TxManager groups repository calls into one transaction. The transaction
+// travels in the context passed to fn: repository methods called with that
+// context, or one derived from it, take part in it.
+type TxManager interface {
+	// WithinTx runs fn in a transaction, committed when fn returns nil and
+	// rolled back otherwise. A call with a context already carrying a
+	// transaction joins it instead of starting another, so the outermost
+	// call decides the outcome. An error of fn is returned as is; a failed
+	// commit is reported as a domain error.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+var errShuttingDown = errors.New("shutting down")
+
+// Failures are reported on the unauthenticated /readyz by these messages;
+// the underlying errors are only logged.
+const (
+	checkFailed   = "check failed"
+	checkTimedOut = "check timed out"
+)
+
+// HealthService reports liveness and readiness. Readiness runs the
+// registered checkers concurrently, each under its own timeout, and caches
+// the results so frequent probes do not hammer the dependencies.
+type HealthService struct {
+	logger   ports.Logger
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	mu       sync.Mutex
+	checkers []ports.HealthChecker
+	cached   *domain.HealthReport
+	cachedAt time.Time
+
+	shuttingDown atomic.Bool
+}
+
+func NewHealthService(logger ports.Logger, timeout, cacheTTL time.Duration) *HealthService {
+	return &HealthService{
+		logger:   logger.With("component", "health"),
+		timeout:  timeout,
+		cacheTTL: cacheTTL,
+	}
+}
+
+// Register adds a dependency checker to the readiness report.
+func (s *HealthService) Register(checker ports.HealthChecker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkers = append(s.checkers, checker)
+	s.cached = nil
+}
+
+// SetShuttingDown marks the service as not ready so load balancers stop
+// routing new requests to it while in-flight ones drain.
+func (s *HealthService) SetShuttingDown() {
+	s.shuttingDown.Store(true)
+}
+
+// Liveness reports whether the process is able to serve requests at all. It
+// does not check dependencies.
+func (s *HealthService) Liveness(ctx context.Context) domain.HealthReport {
+	return domain.HealthReport{Status: domain.HealthStatusUp}
+}
+
+// Readiness reports whether the service and its dependencies can take traffic.
+func (s *HealthService) Readiness(ctx context.Context) domain.HealthReport {
+	if s.shuttingDown.Load() {
+		return domain.HealthReport{
+			Status: domain.HealthStatusDown,
+			Checks: []domain.HealthCheck{{
+				Name:      "lifecycle",
+				Status:    domain.HealthStatusDown,
+				Error:     errShuttingDown.Error(),
+				CheckedAt: time.Now().UTC(),
+			}},
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached != nil && time.Since(s.cachedAt) < s.cacheTTL {
+		return *s.cached
+	}
+
+	// The checks outlive a prober that hangs up, so its cancellation is not
+	// cached as a failure for everyone else.
+	report := s.check(context.WithoutCancel(ctx))
+	if report.Status == domain.HealthStatusDown {
+		s.logger.With("checks", report.Checks).Warn("service is not ready")
+	}
+	s.cached, s.cachedAt = &report, time.Now()
+	return report
+}
+
+// check runs every checker concurrently. Callers must hold s.mu.
+func (s *HealthService) check(ctx context.Context) domain.HealthReport {
+	report := domain.HealthReport{
+		Status: domain.HealthStatusUp,
+		Checks: make([]domain.HealthCheck, len(s.checkers)),
+	}
+
+	var wg sync.WaitGroup
+	for i, checker := range s.checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = s.run(ctx, checker)
+		}()
+	}
+	wg.Wait()
+
+	for _, check := range report.Checks {
+		if check.Status != domain.HealthStatusUp {
+			report.Status = domain.HealthStatusDown
+		}
+	}
+	return report
+}
+
+func (s *HealthService) run(ctx context.Context, checker ports.HealthChecker) domain.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checker.Check(ctx)
+	result := domain.HealthCheck{
+		Name:      checker.Name(),
+		Status:    domain.HealthStatusUp,
+		LatencyMs: time.Since(start).Milliseconds(),
+		CheckedAt: start.UTC(),
+	}
+	if err != nil {
+		result.Status = domain.HealthStatusDown
+		result.Error = checkFailed
+		if errors.Is(err, context.DeadlineExceeded) {
+			result.Error = checkTimedOut
+		}
+		s.logger.WithContext(ctx).
+			With("check", result.Name).
+			With("error", err.Error()).
+			Warn("health check failed")
+	}
+	return result
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/logger"
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+)
+
+// funcChecker is a ports.HealthChecker counting its calls.
+type funcChecker struct {
+	check func(ctx context.Context) error
+	calls atomic.Int32
+}
+
+func (c *funcChecker) Name() string { return "database" }
+
+func (c *funcChecker) Check(ctx context.Context) error {
+	c.calls.Add(1)
+	return c.check(ctx)
+}
+
+// contextChecker fails once ctx is done, as a database ping does.
+func contextChecker() *funcChecker {
+	return &funcChecker{check: func(ctx context.Context) error { return ctx.Err() }}
+}
+
+func newHealthService(timeout, cacheTTL time.Duration, checker *funcChecker) *HealthService {
+	s := NewHealthService(logger.New(logger.WithOutput(io.Discard)), timeout, cacheTTL)
+	s.Register(checker)
+	return s
+}
+
+func TestReadinessCachesResults(t *testing.T) {
+	checker := contextChecker()
+	s := newHealthService(time.Second, time.Hour, checker)
+
+	for range 3 {
+		if report := s.Readiness(context.Background()); report.Status != domain.HealthStatusUp {
+			t.Fatalf("Readiness returned %+v, want up", report)
+		}
+	}
+	if n := checker.calls.Load(); n != 1 {
+		t.Errorf("checker ran %d times within the cache TTL, want 1", n)
+	}
+
+	s.cacheTTL = 0
+	s.Readiness(context.Background())
+	if n := checker.calls.Load(); n != 2 {
+		t.Errorf("checker ran %d times after the cache expired, want 2", n)
+	}
+}
+
+func TestReadinessIgnoresProberCancellation(t *testing.T) {
+	checker := contextChecker()
+	s := newHealthService(time.Second, time.Hour, checker)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if report := s.Readiness(ctx); report.Status != domain.HealthStatusUp {
+		t.Errorf("Readiness for a prober that hung up returned %+v, want up", report)
+	}
+	if report := s.Readiness(context.Background()); report.Status != domain.HealthStatusUp {
+		t.Errorf("Readiness after a prober hung up returned %+v, want up", report)
+	}
+}
+
+func TestReadinessHidesCheckErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		check   func(ctx context.Context) error
+		timeout time.Duration
+		want    string
+	}{
+		{"failure", func(context.Context) error {
+			return errors.New("dial tcp 10.0.0.5:5432: connect: connection refused")
+		}, time.Second, checkFailed},
+		{"timeout", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, 10 * time.Millisecond, checkTimedOut},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newHealthService(tt.timeout, 0, &funcChecker{check: tt.check})
+			report := s.Readiness(context.Background())
+			if report.Status != domain.HealthStatusDown || len(report.Checks) != 1 || report.Checks[0].Error != tt.want {
+				t.Errorf("Readiness returned %+v, want down with %q", report, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadinessWhileShuttingDown(t *testing.T) {
+	checker := contextChecker()
+	s := newHealthService(time.Second, 0, checker)
+	s.SetShuttingDown()
+
+	if report := s.Readiness(context.Background()); report.Status != domain.HealthStatusDown {
+		t.Errorf("Readiness while shutting down returned %+v, want down", report)
+	}
+	if n := checker.calls.Load(); n != 0 {
+		t.Errorf("checker ran %d times while shutting down", n)
+	}
+	if report := s.Liveness(context.Background()); report.Status != domain.HealthStatusUp {
+		t.Errorf("Liveness while shutting down returned %+v, want up", report)
+	}
+}
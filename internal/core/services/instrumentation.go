@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+// Operation outcomes reported to ports.Metrics.
+const (
+	outcomeSuccess  = "success"
+	outcomeInvalid  = "invalid"
+	outcomeDenied   = "denied"
+	outcomeNotFound = "not_found"
+	outcomeConflict = "conflict"
+	outcomeTimeout  = "timeout"
+	outcomeError    = "error"
+)
+
+// noopMetrics discards every measurement.
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveOperation(string, string, time.Duration) {}
+
+// noopTracer creates spans that record nothing.
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, _ string) (context.Context, ports.Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(string, any) {}
+func (noopSpan) RecordError(error)        {}
+func (noopSpan) End()                     {}
+
+// outcome classifies the error returned by a service operation.
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return outcomeSuccess
+	case errors.Is(err, domain.ErrUnauthenticated), errors.Is(err, domain.ErrForbidden),
+		errors.Is(err, domain.ErrItemNotOwned):
+		return outcomeDenied
+	case errors.Is(err, domain.ErrTimeout):
+		return outcomeTimeout
+	case errors.Is(err, domain.ErrItemNotFound):
+		return outcomeNotFound
+	case errors.Is(err, domain.ErrVersionMismatch), errors.Is(err, domain.ErrPatchConflict),
+		errors.Is(err, domain.ErrItemExists):
+		return outcomeConflict
+	case errors.Is(err, domain.ErrInvalidItem), errors.Is(err, domain.ErrInvalidQuery),
+		errors.Is(err, domain.ErrInvalidCursor), errors.Is(err, domain.ErrInvalidPatch),
+		errors.Is(err, domain.ErrInvalidReference), errors.Is(err, domain.ErrInvalidBatch),
+		errors.Is(err, domain.ErrBatchTooLarge):
+		return outcomeInvalid
+	default:
+		return outcomeError
+	}
+}
+
+// instrument starts the span of a service operation and returns the context
+// to run it with, and a function that ends the span and records the metrics.
+// Use it as
+//
+//	ctx, end := s.instrument(ctx, "create_item")
+//	defer end(&err)
+func (s *ItemService) instrument(ctx context.Context, operation string) (context.Context, func(err *error)) {
+	start := time.Now()
+	ctx, span := s.tracer.Start(ctx, "ItemService."+operation)
+	span.SetAttribute("operation", operation)
+	return ctx, func(err *error) {
+		result := outcome(*err)
+		span.SetAttribute("outcome", result)
+		if result == outcomeError {
+			span.RecordError(*err)
+		}
+		span.End()
+		s.metrics.ObserveOperation(operation, result, time.Since(start))
+	}
+}
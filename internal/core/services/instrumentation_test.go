@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/tracing"
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+	"github.com/krisadabig/supreme-ms-item/internal/utils/contextutils"
+)
+
+func TestOutcome(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, outcomeSuccess},
+		{fmt.Errorf("fetch: %w", domain.ErrItemNotFound), outcomeNotFound},
+		{domain.ErrItemNotOwned, outcomeDenied},
+		{domain.ErrForbidden, outcomeDenied},
+		{domain.ErrVersionMismatch, outcomeConflict},
+		{&domain.ValidationError{}, outcomeInvalid},
+		{domain.ErrTimeout, outcomeTimeout},
+		{errors.New("connection reset"), outcomeError},
+	}
+	for _, tt := range tests {
+		if got := outcome(tt.err); got != tt.want {
+			t.Errorf("outcome(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestInstrumentRecordsSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	s := newTestService(WithTracer(tracing.NewTracer(provider, "test")))
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "request")
+	if _, err := s.GetByID(contextutils.ContextWithUser(ctx, alice), 42); !errors.Is(err, domain.ErrItemNotFound) {
+		t.Fatalf("GetByID returned %v, want ErrItemNotFound", err)
+	}
+	parent.End()
+
+	var span sdktrace.ReadOnlySpan
+	for _, ended := range recorder.Ended() {
+		if ended.Name() == "ItemService.get_item_by_id" {
+			span = ended
+		}
+	}
+	if span == nil {
+		t.Fatalf("no service span among %d spans", len(recorder.Ended()))
+	}
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("service span is not a child of the request span")
+	}
+	wantAttr := attribute.String("outcome", outcomeNotFound)
+	found := false
+	for _, attr := range span.Attributes() {
+		found = found || attr == wantAttr
+	}
+	if !found {
+		t.Errorf("span attributes %v lack %v", span.Attributes(), wantAttr)
+	}
+	// Expected failures are outcomes, not span errors.
+	if span.Status().Code != codes.Unset {
+		t.Errorf("span status %v, want unset", span.Status().Code)
+	}
+}
@@ -0,0 +1,336 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+// CreateBatch creates items for the caller, as by Create, and reports the
+// outcome of every item at its index. In atomic mode the items are stored
+// in one transaction; an item that fails fails the whole batch.
+func (s *ItemService) CreateBatch(ctx context.Context, items []*domain.Item, mode domain.BatchMode) (_ []domain.BatchResult, err error) {
+	ctx, end := s.instrument(ctx, "create_items_batch")
+	defer end(&err)
+
+	log := s.logger.WithContext(ctx).
+		With("operation", "create_items_batch").
+		With("mode", string(mode)).
+		With("count", len(items))
+
+	callerID, err := s.startBatch(ctx, log, len(items), mode)
+	if err != nil {
+		return nil, err
+	}
+	log = log.With("user_id", callerID)
+
+	// prepareCreate clears any ID sent, so the items cannot collide with one
+	// another or with stored items.
+	results := make([]domain.BatchResult, len(items))
+	for i, item := range items {
+		results[i].Err = prepareCreate(callerID, item)
+	}
+
+	if mode == domain.BatchPartial {
+		for i, item := range items {
+			if results[i].Err != nil {
+				continue
+			}
+			if err := s.repo.Create(ctx, item); err != nil {
+				results[i].Err = fmt.Errorf("failed to create item: %w", err)
+				continue
+			}
+			results[i].Item = item
+		}
+		logBatch(log, results)
+		return results, nil
+	}
+
+	if failed(results) {
+		abortBatch(results)
+		logBatch(log, results)
+		return results, nil
+	}
+	log.Info("creating items")
+	if err := s.repo.CreateMany(ctx, items); err != nil {
+		return failBatch(log, results, nil, fmt.Errorf("failed to create items: %w", err))
+	}
+	for i, item := range items {
+		results[i].Item = item
+	}
+	logBatch(log, results)
+	return results, nil
+}
+
+// ReplaceBatch replaces the caller's items, as by Replace, with items
+// identified by their ID. A non-zero Version of an item must match the
+// stored version. In atomic mode the items are read and updated in one
+// transaction; an item that fails fails the whole batch.
+func (s *ItemService) ReplaceBatch(ctx context.Context, items []*domain.Item, mode domain.BatchMode) (_ []domain.BatchResult, err error) {
+	ctx, end := s.instrument(ctx, "replace_items_batch")
+	defer end(&err)
+
+	log := s.logger.WithContext(ctx).
+		With("operation", "replace_items_batch").
+		With("mode", string(mode)).
+		With("count", len(items))
+
+	callerID, err := s.startBatch(ctx, log, len(items), mode)
+	if err != nil {
+		return nil, err
+	}
+	log = log.With("user_id", callerID)
+
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	if err := checkDistinct(ids); err != nil {
+		log.With("error", err.Error()).Warn("invalid batch")
+		return nil, err
+	}
+
+	if mode == domain.BatchPartial {
+		results, writes, indexes := s.loadReplacements(ctx, items)
+		for j, item := range writes {
+			if err := s.repo.Update(ctx, item, domain.MutableItemFields); err != nil {
+				results[indexes[j]] = domain.BatchResult{Err: fmt.Errorf("failed to update item: %w", err)}
+			}
+		}
+		logBatch(log, results)
+		return results, nil
+	}
+
+	// The items are read in the transaction that writes them, so none
+	// changes in between.
+	var (
+		results []domain.BatchResult
+		indexes []int
+	)
+	err = s.inTx(ctx, func(ctx context.Context) error {
+		var writes []*domain.Item
+		results, writes, indexes = s.loadReplacements(ctx, items)
+		if failed(results) || len(writes) == 0 {
+			return nil
+		}
+		log.With("changed", len(writes)).Info("updating items")
+		if err := s.repo.UpdateMany(ctx, writes, domain.MutableItemFields); err != nil {
+			return fmt.Errorf("failed to update items: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return failBatch(log, results, indexes, err)
+	}
+	if failed(results) {
+		abortBatch(results)
+	}
+	logBatch(log, results)
+	return results, nil
+}
+
+// loadReplacements builds the replacement of every item of a batch. It
+// returns the result of every item, and the replacements that change their
+// item with their indexes in the batch; items left unchanged are not written.
+func (s *ItemService) loadReplacements(ctx context.Context, items []*domain.Item) (results []domain.BatchResult, writes []*domain.Item, indexes []int) {
+	results = make([]domain.BatchResult, len(items))
+	for i, item := range items {
+		current, err := s.loadForUpdate(ctx, item.ID, item.Version)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		updated, changed, err := replacement(current, item)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Item = updated
+		if len(changed) > 0 {
+			writes = append(writes, updated)
+			indexes = append(indexes, i)
+		}
+	}
+	return results, writes, indexes
+}
+
+// DeleteBatch moves the caller's referenced items to the trash, as by
+// Delete. In atomic mode the items are trashed in one transaction; an item
+// that fails fails the whole batch.
+func (s *ItemService) DeleteBatch(ctx context.Context, refs []domain.ItemRef, mode domain.BatchMode) (_ []domain.BatchResult, err error) {
+	ctx, end := s.instrument(ctx, "delete_items_batch")
+	defer end(&err)
+
+	log := s.logger.WithContext(ctx).
+		With("operation", "delete_items_batch").
+		With("mode", string(mode)).
+		With("count", len(refs))
+
+	callerID, err := s.startBatch(ctx, log, len(refs), mode)
+	if err != nil {
+		return nil, err
+	}
+	log = log.With("user_id", callerID)
+
+	ids := make([]int64, len(refs))
+	for i, ref := range refs {
+		ids[i] = ref.ID
+	}
+	if err := checkDistinct(ids); err != nil {
+		log.With("error", err.Error()).Warn("invalid batch")
+		return nil, err
+	}
+
+	results := make([]domain.BatchResult, len(refs))
+	for i, ref := range refs {
+		if ref.ID <= 0 {
+			results[i].Err = domain.ErrInvalidItem
+		}
+	}
+
+	if mode == domain.BatchPartial {
+		for i, ref := range refs {
+			if results[i].Err != nil {
+				continue
+			}
+			if err := s.repo.Delete(ctx, callerID, ref.ID, ref.Version); err != nil {
+				results[i].Err = fmt.Errorf("failed to delete item: %w", err)
+			}
+		}
+		logBatch(log, results)
+		return results, nil
+	}
+
+	if failed(results) {
+		abortBatch(results)
+		logBatch(log, results)
+		return results, nil
+	}
+	log.Info("deleting items")
+	if err := s.repo.DeleteMany(ctx, callerID, refs); err != nil {
+		return failBatch(log, results, nil, fmt.Errorf("failed to delete items: %w", err))
+	}
+	logBatch(log, results)
+	return results, nil
+}
+
+// startBatch checks the caller and the size and mode of a batch of n items.
+func (s *ItemService) startBatch(ctx context.Context, log ports.Logger, n int, mode domain.BatchMode) (string, error) {
+	callerID, err := callerIDFromContext(ctx)
+	if err != nil {
+		log.Warn("caller identity is missing")
+		return "", err
+	}
+	if err := s.CheckBatch(n, mode); err != nil {
+		log.With("error", err.Error()).Warn("invalid batch")
+		return "", err
+	}
+	return callerID, nil
+}
+
+// CheckBatch reports whether a batch of n items in mode is acceptable, so
+// callers can reject a batch before decoding its items.
+func (s *ItemService) CheckBatch(n int, mode domain.BatchMode) error {
+	switch {
+	case mode != domain.BatchAtomic && mode != domain.BatchPartial:
+		return fmt.Errorf("%w: mode must be %s or %s", domain.ErrInvalidBatch, domain.BatchAtomic, domain.BatchPartial)
+	case n == 0:
+		return fmt.Errorf("%w: no items given", domain.ErrInvalidBatch)
+	case n > s.maxBatchSize:
+		return fmt.Errorf("%w: %d items given, at most %d allowed", domain.ErrBatchTooLarge, n, s.maxBatchSize)
+	}
+	return nil
+}
+
+// MaxBatchSize returns the number of items a batch may hold.
+func (s *ItemService) MaxBatchSize() int {
+	return s.maxBatchSize
+}
+
+// checkDistinct rejects batches addressing the same item more than once.
+func checkDistinct(ids []int64) error {
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if id != 0 && seen[id] {
+			return fmt.Errorf("%w: item %d appears more than once", domain.ErrInvalidBatch, id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// replacement builds the state of current once its mutable fields are
+// replaced by those of item, and lists the fields that change.
+func replacement(current, item *domain.Item) (*domain.Item, []string, error) {
+	if err := checkImmutable(current, item); err != nil {
+		return nil, nil, err
+	}
+
+	updated := *current
+	updated.Title = item.Title
+	updated.Description = item.Description
+	if err := updated.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("validation failed: %w", err)
+	}
+	return &updated, changedFields(current, &updated), nil
+}
+
+func failed(results []domain.BatchResult) bool {
+	for _, r := range results {
+		if r.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// abortBatch marks the items of an atomic batch that were not written
+// because another item failed.
+func abortBatch(results []domain.BatchResult) {
+	for i := range results {
+		results[i].Item = nil
+		if results[i].Err == nil {
+			results[i].Err = domain.ErrBatchAborted
+		}
+	}
+}
+
+// failBatch handles the failed write of an atomic batch. A failure of one
+// item, reported by the repository at its position among the written items
+// which indexes maps back to the batch (nil when every item was written), is
+// recorded for that item; any other failure concerns the batch as a whole
+// and is returned.
+func failBatch(log ports.Logger, results []domain.BatchResult, indexes []int, err error) ([]domain.BatchResult, error) {
+	var itemErr *domain.BatchItemError
+	if !errors.As(err, &itemErr) {
+		log.Error("failed to write batch", err)
+		return nil, err
+	}
+
+	i := itemErr.Index
+	if indexes != nil {
+		i = indexes[i]
+	}
+	results[i].Err = itemErr.Err
+	abortBatch(results)
+	logBatch(log, results)
+	return results, nil
+}
+
+// logBatch reports how many items of a batch failed.
+func logBatch(log ports.Logger, results []domain.BatchResult) {
+	n := 0
+	for _, r := range results {
+		if r.Err != nil {
+			n++
+		}
+	}
+	if n == 0 {
+		log.Info("batch processed successfully")
+		return
+	}
+	log.With("failed", n).Warn("batch processed with failures")
+}
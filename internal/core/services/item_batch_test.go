@@ -0,0 +1,27 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+)
+
+func TestCreateBatchIgnoresClientIDs(t *testing.T) {
+	s := newTestService()
+	ctx := as(alice)
+
+	first, second := "first", "second"
+	items := []*domain.Item{{ID: 5, Title: &first}, {ID: 5, Title: &second}}
+	results, err := s.CreateBatch(ctx, items, domain.BatchAtomic)
+	if err != nil {
+		t.Fatalf("CreateBatch: %v", err)
+	}
+	for i, r := range results {
+		if r.Err != nil {
+			t.Fatalf("item %d failed: %v", i, r.Err)
+		}
+	}
+	if results[0].Item.ID == results[1].Item.ID {
+		t.Errorf("both items were stored with ID %d", results[0].Item.ID)
+	}
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+)
+
+// applyPatch applies a JSON Merge Patch or JSON Patch document to the JSON
+// representation of item and decodes the result into a new item.
+func applyPatch(item *domain.Item, patchType domain.PatchType, doc []byte) (*domain.Item, error) {
+	original, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	switch patchType {
+	case domain.PatchTypeMerge:
+		patched, err = jsonpatch.MergePatch(original, doc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidPatch, err)
+		}
+	case domain.PatchTypeJSON:
+		patch, err := jsonpatch.DecodePatch(doc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidPatch, err)
+		}
+		patched, err = patch.Apply(original)
+		if errors.Is(err, jsonpatch.ErrTestFailed) || errors.Is(err, jsonpatch.ErrMissing) {
+			return nil, fmt.Errorf("%w: %v", domain.ErrPatchConflict, err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidPatch, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported patch type %q", domain.ErrInvalidPatch, patchType)
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(patched, &members); err != nil {
+		return nil, fmt.Errorf("%w: patched document is not an object", domain.ErrInvalidPatch)
+	}
+
+	var verr domain.ValidationError
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !domain.IsItemField(name) {
+			verr.Add(name, domain.CodeUnknownField, "unknown field "+name)
+		}
+	}
+
+	var result domain.Item
+	if err := json.Unmarshal(patched, &result); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidPatch, err)
+		}
+		verr.Add(typeErr.Field, domain.CodeInvalidType, typeErr.Field+" must be of type "+typeErr.Type.String())
+	}
+
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// checkImmutable reports the service managed fields that update tries to
+// change. Zero values are treated as omitted.
+func checkImmutable(current, update *domain.Item) error {
+	var verr domain.ValidationError
+	immutable := func(field string) {
+		verr.Add(field, domain.CodeImmutable, field+" cannot be changed")
+	}
+
+	if update.ID != 0 && update.ID != current.ID {
+		immutable(domain.ItemFieldID)
+	}
+	if update.UserID != "" && update.UserID != current.UserID {
+		immutable(domain.ItemFieldUserID)
+	}
+	if !update.CreatedAt.IsZero() && !update.CreatedAt.Equal(current.CreatedAt) {
+		immutable(domain.ItemFieldCreatedAt)
+	}
+	if !update.UpdatedAt.IsZero() && !update.UpdatedAt.Equal(current.UpdatedAt) {
+		immutable(domain.ItemFieldUpdatedAt)
+	}
+	if update.DeletedAt != nil && (current.DeletedAt == nil || !update.DeletedAt.Equal(*current.DeletedAt)) {
+		immutable(domain.ItemFieldDeletedAt)
+	}
+	// Clients state the version they expect with If-Match.
+	if update.Version != 0 && update.Version != current.Version {
+		immutable(domain.ItemFieldVersion)
+	}
+
+	return verr.Err()
+}
+
+// changedFields returns the mutable fields whose value differs between
+// current and update.
+func changedFields(current, update *domain.Item) []string {
+	var fields []string
+	if !equalStringPtr(current.Title, update.Title) {
+		fields = append(fields, domain.ItemFieldTitle)
+	}
+	if !equalStringPtr(current.Description, update.Description) {
+		fields = append(fields, domain.ItemFieldDescription)
+	}
+	return fields
+}
+
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+)
+
+func TestApplyPatchRejectsServerManagedFields(t *testing.T) {
+	title := "title"
+	current := &domain.Item{
+		ID:        1,
+		Title:     &title,
+		UserID:    "alice",
+		CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		Version:   3,
+	}
+
+	tests := []struct {
+		name      string
+		patchType domain.PatchType
+		doc       string
+		field     string
+	}{
+		{"merge id", domain.PatchTypeMerge, `{"id": 2}`, domain.ItemFieldID},
+		{"merge user_id", domain.PatchTypeMerge, `{"user_id": "bob"}`, domain.ItemFieldUserID},
+		{"merge created_at", domain.PatchTypeMerge, `{"created_at": "2000-01-01T00:00:00Z"}`, domain.ItemFieldCreatedAt},
+		{"merge version", domain.PatchTypeMerge, `{"version": 77}`, domain.ItemFieldVersion},
+		{"json patch version", domain.PatchTypeJSON, `[{"op": "replace", "path": "/version", "value": 77}]`, domain.ItemFieldVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, err := applyPatch(current, tt.patchType, []byte(tt.doc))
+			if err != nil {
+				t.Fatalf("applyPatch: %v", err)
+			}
+			err = checkImmutable(current, updated)
+			var verr *domain.ValidationError
+			if !errors.As(err, &verr) || !hasViolation(verr, tt.field, domain.CodeImmutable) {
+				t.Errorf("checkImmutable returned %v, want %s to be immutable", err, tt.field)
+			}
+		})
+	}
+
+	// Echoing the current version is no change.
+	updated, err := applyPatch(current, domain.PatchTypeMerge, []byte(`{"title": "new", "version": 3}`))
+	if err != nil {
+		t.Fatalf("applyPatch: %v", err)
+	}
+	if err := checkImmutable(current, updated); err != nil {
+		t.Errorf("checkImmutable of an unchanged version returned %v", err)
+	}
+}
+
+func hasViolation(verr *domain.ValidationError, field, code string) bool {
+	for _, e := range verr.Errors {
+		if e.Field == field && e.Code == code {
+			return true
+		}
+	}
+	return false
+}
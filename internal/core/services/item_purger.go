@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+// ItemPurger periodically removes items that have been in the trash for
+// longer than the retention period.
+type ItemPurger struct {
+	repo      ports.ItemRepository
+	logger    ports.Logger
+	retention time.Duration
+	interval  time.Duration
+}
+
+func NewItemPurger(repo ports.ItemRepository, logger ports.Logger, retention, interval time.Duration) *ItemPurger {
+	return &ItemPurger{
+		repo:      repo,
+		logger:    logger,
+		retention: retention,
+		interval:  interval,
+	}
+}
+
+// Run purges expired items every interval until ctx is cancelled.
+func (p *ItemPurger) Run(ctx context.Context) {
+	log := p.logger.With("worker", "item_purger")
+	if p.retention <= 0 || p.interval <= 0 {
+		log.Info("item purging disabled")
+		return
+	}
+
+	log.With("retention", p.retention.String()).
+		With("interval", p.interval.String()).
+		Info("item purger started")
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.PurgeOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Info("item purger stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeOnce removes the items trashed before now minus the retention period.
+func (p *ItemPurger) PurgeOnce(ctx context.Context) {
+	cutoff := time.Now().UTC().Add(-p.retention)
+	log := p.logger.WithContext(ctx).
+		With("operation", "purge_items").
+		With("deleted_before", cutoff)
+
+	purged, err := p.repo.Purge(ctx, cutoff)
+	if err != nil {
+		log.Error("failed to purge trashed items", err)
+		return
+	}
+
+	if purged > 0 {
+		log.With("count", purged).Info("purged trashed items")
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/logger"
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/storage/memory"
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+)
+
+func TestPurgeOnce(t *testing.T) {
+	repo := memory.NewMemoryItemRepository()
+	log := logger.New(logger.WithOutput(io.Discard))
+	s := NewItemService(repo, log)
+	trashed := createItem(t, s, alice, "trashed")
+	kept := createItem(t, s, alice, "kept")
+	if err := s.Delete(as(alice), trashed.ID, 0); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	NewItemPurger(repo, log, time.Hour, time.Hour).PurgeOnce(context.Background())
+	if page, err := s.GetDeleted(as(admin), domain.ItemQuery{}); err != nil || len(page.Items) != 1 {
+		t.Fatalf("GetDeleted after purging within the retention returned %+v, %v, want the trashed item", page, err)
+	}
+
+	time.Sleep(time.Millisecond)
+	NewItemPurger(repo, log, time.Nanosecond, time.Hour).PurgeOnce(context.Background())
+	if page, err := s.GetDeleted(as(admin), domain.ItemQuery{}); err != nil || len(page.Items) != 0 {
+		t.Errorf("GetDeleted after purging past the retention returned %+v, %v, want no items", page, err)
+	}
+	if _, err := s.Restore(as(alice), trashed.ID); err == nil {
+		t.Error("Restore of a purged item succeeded")
+	}
+	if _, err := s.GetByID(as(alice), kept.ID); err != nil {
+		t.Errorf("GetByID of an item out of the trash after purging: %v", err)
+	}
+}
+
+func TestRunWithoutRetentionReturns(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		NewItemPurger(memory.NewMemoryItemRepository(), logger.New(logger.WithOutput(io.Discard)), 0, time.Hour).Run(context.Background())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run with purging disabled did not return")
+	}
+}
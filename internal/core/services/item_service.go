@@ -2,30 +2,107 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/krisadabig/supreme-ms-item/internal/constants"
 	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
 	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+	"github.com/krisadabig/supreme-ms-item/internal/utils/contextutils"
 )
 
 type ItemService struct {
-	repo   ports.ItemRepository
-	logger ports.Logger
+	repo    ports.ItemRepository
+	logger  ports.Logger
+	metrics ports.Metrics
+	tracer  ports.Tracer
+	tx      ports.TxManager
+
+	queryTimeout time.Duration
+	maxBatchSize int
+}
+
+// ItemServiceOption configures optional dependencies of an ItemService.
+type ItemServiceOption func(*ItemService)
+
+// WithMetrics reports the outcome and duration of every operation to m.
+func WithMetrics(m ports.Metrics) ItemServiceOption {
+	return func(s *ItemService) {
+		s.metrics = m
+	}
 }
 
-func NewItemService(repo ports.ItemRepository, logger ports.Logger) *ItemService {
-	return &ItemService{
-		repo:   repo,
-		logger: logger,
+// WithQueryTimeout bounds every repository call by timeout, so a slow query
+// is cancelled and reported as domain.ErrTimeout. Zero disables the deadline.
+func WithQueryTimeout(timeout time.Duration) ItemServiceOption {
+	return func(s *ItemService) {
+		s.queryTimeout = timeout
 	}
 }
 
-func (s *ItemService) Create(ctx context.Context, item *domain.Item) error {
+// WithMaxBatchSize limits the number of items of a batch operation to n.
+func WithMaxBatchSize(n int) ItemServiceOption {
+	return func(s *ItemService) {
+		s.maxBatchSize = n
+	}
+}
+
+// WithTxManager runs the operations that read an item before writing it in
+// transactions of m, retried when they lose to a concurrent transaction.
+// Without it every repository call stands alone and the version check of the
+// write is the only guard against concurrent changes.
+func WithTxManager(m ports.TxManager) ItemServiceOption {
+	return func(s *ItemService) {
+		s.tx = m
+	}
+}
+
+// WithTracer wraps every operation in a span started by t.
+func WithTracer(t ports.Tracer) ItemServiceOption {
+	return func(s *ItemService) {
+		s.tracer = t
+	}
+}
+
+func NewItemService(repo ports.ItemRepository, logger ports.Logger, opts ...ItemServiceOption) *ItemService {
+	s := &ItemService{
+		repo:    repo,
+		logger:  logger,
+		metrics: noopMetrics{},
+		tracer:  noopTracer{},
+		tx:      noopTxManager{},
+
+		queryTimeout: time.Duration(constants.DBTimeout) * time.Second,
+		maxBatchSize: domain.DefaultMaxBatchSize,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.queryTimeout > 0 {
+		s.repo = &timeoutRepository{repo: s.repo, timeout: s.queryTimeout}
+	}
+	return s
+}
+
+func (s *ItemService) Create(ctx context.Context, item *domain.Item) (err error) {
+	ctx, end := s.instrument(ctx, "create_item")
+	defer end(&err)
+
 	log := s.logger.WithContext(ctx).With("operation", "create_item")
 
-	if err := item.Validate(); err != nil {
-		log.Error("validation failed", err)
-		return fmt.Errorf("validation failed: %w", err)
+	callerID, err := callerIDFromContext(ctx)
+	if err != nil {
+		log.Warn("caller identity is missing")
+		return err
+	}
+	if err := prepareCreate(callerID, item); err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			log.With("user_id", item.UserID).Warn("refusing to create item for another user")
+		} else {
+			log.Error("validation failed", err)
+		}
+		return err
 	}
 
 	title := ""
@@ -37,7 +114,7 @@ func (s *ItemService) Create(ctx context.Context, item *domain.Item) error {
 		With("title", title).
 		Info("creating item")
 
-	if err := s.repo.Create(item); err != nil {
+	if err := s.repo.Create(ctx, item); err != nil {
 		log.With("user_id", item.UserID).
 			With("title", title).
 			Error("failed to create item", err)
@@ -50,25 +127,150 @@ func (s *ItemService) Create(ctx context.Context, item *domain.Item) error {
 	return nil
 }
 
-func (s *ItemService) Update(ctx context.Context, item *domain.Item) error {
+// prepareCreate turns item into a new item of callerID and validates it.
+// The fields the store manages are cleared, so clients can neither pick the
+// ID of an item nor backdate it.
+func prepareCreate(callerID string, item *domain.Item) error {
+	if item.UserID != "" && item.UserID != callerID {
+		return domain.ErrForbidden
+	}
+	item.ID = 0
+	item.UserID = callerID
+	item.CreatedAt = time.Time{}
+	item.UpdatedAt = time.Time{}
+	item.DeletedAt = nil
+	item.Version = 1
+
+	if err := item.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	return nil
+}
+
+// Replace overwrites the mutable fields of the item identified by id with
+// those of item, clearing any that item leaves unset. A non-zero
+// expectedVersion must match the stored version. On success item holds the
+// stored state. The item is read and written in one transaction.
+func (s *ItemService) Replace(ctx context.Context, id, expectedVersion int64, item *domain.Item) (err error) {
+	ctx, end := s.instrument(ctx, "replace_item")
+	defer end(&err)
+
+	log := s.logger.WithContext(ctx).
+		With("operation", "replace_item").
+		With("item_id", id)
+
+	var updated domain.Item
+	err = s.inTx(ctx, func(ctx context.Context) error {
+		current, err := s.loadForUpdate(ctx, id, expectedVersion)
+		if err != nil {
+			log.Error("failed to load item for replacement", err)
+			return err
+		}
+
+		if err := checkImmutable(current, item); err != nil {
+			log.With("error", err.Error()).Warn("refusing to change immutable fields")
+			return err
+		}
+
+		updated = *current
+		updated.Title = item.Title
+		updated.Description = item.Description
+		return s.update(ctx, current, &updated)
+	})
+	if err != nil {
+		return err
+	}
+	*item = updated
+	return nil
+}
+
+// Patch applies a JSON Merge Patch or JSON Patch document to the item
+// identified by id. Only the fields the patch changes are written. A non-zero
+// expectedVersion must match the stored version. The item is read and written
+// in one transaction.
+func (s *ItemService) Patch(ctx context.Context, id, expectedVersion int64, patchType domain.PatchType, doc []byte) (_ *domain.Item, err error) {
+	ctx, end := s.instrument(ctx, "patch_item")
+	defer end(&err)
+
+	log := s.logger.WithContext(ctx).
+		With("operation", "patch_item").
+		With("item_id", id).
+		With("patch_type", string(patchType))
+
+	var result domain.Item
+	err = s.inTx(ctx, func(ctx context.Context) error {
+		current, err := s.loadForUpdate(ctx, id, expectedVersion)
+		if err != nil {
+			log.Error("failed to load item for patching", err)
+			return err
+		}
+
+		updated, err := applyPatch(current, patchType, doc)
+		if err != nil {
+			log.With("error", err.Error()).Warn("failed to apply patch")
+			return err
+		}
+
+		if err := checkImmutable(current, updated); err != nil {
+			log.With("error", err.Error()).Warn("refusing to change immutable fields")
+			return err
+		}
+
+		// Carry over the service managed fields the patch may have dropped.
+		result = *current
+		result.Title = updated.Title
+		result.Description = updated.Description
+		return s.update(ctx, current, &result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// loadForUpdate fetches the caller's item that is about to be modified and
+// checks it is at expectedVersion, unless that is zero.
+func (s *ItemService) loadForUpdate(ctx context.Context, id, expectedVersion int64) (*domain.Item, error) {
+	callerID, err := callerIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if id == 0 {
+		return nil, domain.ErrInvalidItem
+	}
+
+	current, err := s.repo.GetByID(ctx, callerID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch item for update: %w", err)
+	}
+
+	if expectedVersion != 0 && current.Version != expectedVersion {
+		return nil, domain.ErrVersionMismatch
+	}
+	return current, nil
+}
+
+// update validates updated and writes the fields that differ from current.
+func (s *ItemService) update(ctx context.Context, current, updated *domain.Item) error {
 	log := s.logger.WithContext(ctx).
 		With("operation", "update_item").
-		With("item_id", item.ID).
-		With("user_id", item.UserID)
+		With("item_id", current.ID).
+		With("user_id", current.UserID)
 
-	if err := item.Validate(); err != nil {
+	if err := updated.Validate(); err != nil {
 		log.Error("validation failed", err)
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
-	if item.ID == 0 {
-		log.Error("cannot update item with id 0", nil)
-		return domain.ErrInvalidItem
+	fields := changedFields(current, updated)
+	if len(fields) == 0 {
+		log.Debug("item unchanged, skipping update")
+		return nil
 	}
 
-	log.Info("updating item")
-	err := s.repo.Update(item)
-	if err != nil {
+	log.With("fields", fields).Info("updating item")
+	if err := s.repo.Update(ctx, updated, fields); err != nil {
 		log.Error("failed to update item", err)
 		return fmt.Errorf("failed to update item: %w", err)
 	}
@@ -77,26 +279,38 @@ func (s *ItemService) Update(ctx context.Context, item *domain.Item) error {
 	return nil
 }
 
-func (s *ItemService) Delete(ctx context.Context, item *domain.Item) error {
+// Delete moves the item to the trash. A non-zero expectedVersion must match
+// the stored version.
+func (s *ItemService) Delete(ctx context.Context, id, expectedVersion int64) (err error) {
+	ctx, end := s.instrument(ctx, "delete_item")
+	defer end(&err)
+
 	log := s.logger.WithContext(ctx).
 		With("operation", "delete_item").
-		With("item_id", item.ID).
-		With("user_id", item.UserID)
+		With("item_id", id)
 
-	if item.ID == 0 {
-		log.Error("cannot delete item with id 0", nil)
-		return domain.ErrInvalidItem
+	callerID, err := callerIDFromContext(ctx)
+	if err != nil {
+		log.Warn("caller identity is missing")
+		return err
 	}
+	log = log.With("user_id", callerID)
 
-	// Check if item exists
-	if _, err := s.repo.GetByID(item.ID); err != nil {
-		log.Error("Item not found for deletion", err)
-		return fmt.Errorf("item not found: %w", err)
+	if id == 0 {
+		log.Error("cannot delete item with id 0", nil)
+		return domain.ErrInvalidItem
 	}
 
+	// The repository only deletes the item when it belongs to the caller,
+	// so existence and ownership are checked in the same statement. Only a
+	// failure is looked into, to tell a foreign item from a missing one.
 	log.Info("deleting item")
-	err := s.repo.Delete(item)
-	if err != nil {
+	if err := s.repo.Delete(ctx, callerID, id, expectedVersion); err != nil {
+		if errors.Is(err, domain.ErrItemNotFound) {
+			if _, lookupErr := s.repo.GetByID(ctx, callerID, id); errors.Is(lookupErr, domain.ErrItemNotOwned) {
+				err = lookupErr
+			}
+		}
 		log.Error("failed to delete item", err)
 		return fmt.Errorf("failed to delete item: %w", err)
 	}
@@ -105,28 +319,91 @@ func (s *ItemService) Delete(ctx context.Context, item *domain.Item) error {
 	return nil
 }
 
-func (s *ItemService) GetAll(ctx context.Context) ([]domain.Item, error) {
+func (s *ItemService) Restore(ctx context.Context, id int64) (_ *domain.Item, err error) {
+	ctx, end := s.instrument(ctx, "restore_item")
+	defer end(&err)
+
+	log := s.logger.WithContext(ctx).
+		With("operation", "restore_item").
+		With("item_id", id)
+
+	callerID, err := callerIDFromContext(ctx)
+	if err != nil {
+		log.Warn("caller identity is missing")
+		return nil, err
+	}
+	log = log.With("user_id", callerID)
+
+	if id == 0 {
+		log.Error("cannot restore item with id 0", nil)
+		return nil, domain.ErrInvalidItem
+	}
+
+	log.Info("restoring item")
+	item, err := s.repo.Restore(ctx, callerID, id)
+	if err != nil {
+		log.Error("failed to restore item", err)
+		return nil, fmt.Errorf("failed to restore item: %w", err)
+	}
+
+	log.Info("item restored successfully")
+	return item, nil
+}
+
+func (s *ItemService) GetAll(ctx context.Context, query domain.ItemQuery) (_ *domain.ItemPage, err error) {
+	ctx, end := s.instrument(ctx, "get_all_items")
+	defer end(&err)
+
 	log := s.logger.WithContext(ctx).With("operation", "get_all_items")
 
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		log.Warn("caller identity is missing")
+		return nil, err
+	}
+	if !caller.IsAdmin() {
+		log.With("caller_id", caller.ID).Warn("refusing to list items of all users for non-admin")
+		return nil, domain.ErrForbidden
+	}
+	if query.UserID != "" {
+		log = log.With("user_id", query.UserID)
+	}
+
+	if err := query.Normalize(); err != nil {
+		log.With("error", err.Error()).Warn("invalid list query")
+		return nil, err
+	}
+
 	log.Debug("fetching all items")
 
-	items, err := s.repo.GetAll()
+	page, err := s.repo.GetAll(ctx, query)
 	if err != nil {
 		log.Error("failed to fetch items", err)
 		return nil, fmt.Errorf("failed to fetch items: %w", err)
 	}
 
-	log.With("count", len(items)).Debug("successfully fetched items")
-	return items, nil
+	log.With("count", len(page.Items)).
+		With("has_more", page.HasMore).
+		Debug("successfully fetched items")
+	return page, nil
 }
 
-func (s *ItemService) GetByID(ctx context.Context, id int64) (*domain.Item, error) {
+func (s *ItemService) GetByID(ctx context.Context, id int64) (_ *domain.Item, err error) {
+	ctx, end := s.instrument(ctx, "get_item_by_id")
+	defer end(&err)
+
 	log := s.logger.WithContext(ctx).
 		With("operation", "get_item_by_id").
 		With("item_id", id)
 
+	callerID, err := callerIDFromContext(ctx)
+	if err != nil {
+		log.Warn("caller identity is missing")
+		return nil, err
+	}
+
 	log.Debug("fetching item by id")
-	item, err := s.repo.GetByID(id)
+	item, err := s.repo.GetByID(ctx, callerID, id)
 	if err != nil {
 		log.Error("failed to fetch item by id", err)
 		return nil, fmt.Errorf("failed to fetch item by ID: %w", err)
@@ -136,18 +413,90 @@ func (s *ItemService) GetByID(ctx context.Context, id int64) (*domain.Item, erro
 	return item, nil
 }
 
-func (s *ItemService) GetByUserID(ctx context.Context, userID string) ([]domain.Item, error) {
+func (s *ItemService) GetByUserID(ctx context.Context, userID string, query domain.ItemQuery) (_ *domain.ItemPage, err error) {
+	ctx, end := s.instrument(ctx, "get_items_by_user_id")
+	defer end(&err)
+
 	log := s.logger.WithContext(ctx).
 		With("operation", "get_items_by_user_id").
 		With("user_id", userID)
 
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		log.Warn("caller identity is missing")
+		return nil, err
+	}
+	if !caller.CanAccessUser(userID) {
+		log.With("caller_id", caller.ID).Warn("refusing to list items of another user")
+		return nil, domain.ErrForbidden
+	}
+
+	if err := query.Normalize(); err != nil {
+		log.With("error", err.Error()).Warn("invalid list query")
+		return nil, err
+	}
+
 	log.Debug("fetching items by user id")
-	items, err := s.repo.GetByUserID(userID)
+	page, err := s.repo.GetByUserID(ctx, userID, query)
 	if err != nil {
 		log.Error("failed to fetch items by user id", err)
 		return nil, fmt.Errorf("failed to fetch items by user ID: %w", err)
 	}
 
-	log.With("count", len(items)).Debug("successfully fetched items by user id")
-	return items, nil
+	log.With("count", len(page.Items)).
+		With("has_more", page.HasMore).
+		Debug("successfully fetched items by user id")
+	return page, nil
+}
+
+func (s *ItemService) GetDeleted(ctx context.Context, query domain.ItemQuery) (_ *domain.ItemPage, err error) {
+	ctx, end := s.instrument(ctx, "get_deleted_items")
+	defer end(&err)
+
+	log := s.logger.WithContext(ctx).With("operation", "get_deleted_items")
+
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		log.Warn("caller identity is missing")
+		return nil, err
+	}
+	if !caller.IsAdmin() {
+		log.With("caller_id", caller.ID).Warn("refusing to list trashed items for non-admin")
+		return nil, domain.ErrForbidden
+	}
+
+	if err := query.Normalize(); err != nil {
+		log.With("error", err.Error()).Warn("invalid list query")
+		return nil, err
+	}
+
+	log.Debug("fetching trashed items")
+	page, err := s.repo.GetDeleted(ctx, query)
+	if err != nil {
+		log.Error("failed to fetch trashed items", err)
+		return nil, fmt.Errorf("failed to fetch trashed items: %w", err)
+	}
+
+	log.With("count", len(page.Items)).
+		With("has_more", page.HasMore).
+		Debug("successfully fetched trashed items")
+	return page, nil
+}
+
+// callerFromContext returns the authenticated user the request runs as.
+func callerFromContext(ctx context.Context) (*domain.User, error) {
+	caller, ok := contextutils.UserFromContext(ctx)
+	if !ok || caller.ID == "" {
+		return nil, domain.ErrUnauthenticated
+	}
+	return caller, nil
+}
+
+// callerIDFromContext returns the ID of the authenticated user the request runs as.
+func callerIDFromContext(ctx context.Context) (string, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	return caller.ID, nil
 }
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/logger"
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/storage/memory"
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+	"github.com/krisadabig/supreme-ms-item/internal/utils/contextutils"
+)
+
+// newTestService returns a service over an empty in-memory repository.
+func newTestService(opts ...ItemServiceOption) *ItemService {
+	return NewItemService(memory.NewMemoryItemRepository(), logger.New(logger.WithOutput(io.Discard)), opts...)
+}
+
+// as returns a context authenticated as user.
+func as(user *domain.User) context.Context {
+	return contextutils.ContextWithUser(context.Background(), user)
+}
+
+var (
+	alice = &domain.User{ID: "alice"}
+	bob   = &domain.User{ID: "bob"}
+	admin = &domain.User{ID: "root", Roles: []string{domain.RoleAdmin}}
+)
+
+// createItem stores an item titled title as user.
+func createItem(t *testing.T, s *ItemService, user *domain.User, title string) *domain.Item {
+	t.Helper()
+	item := &domain.Item{Title: &title}
+	if err := s.Create(as(user), item); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return item
+}
+
+func TestPrepareCreate(t *testing.T) {
+	title := "title"
+	deletedAt := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	item := &domain.Item{
+		ID:        500,
+		Title:     &title,
+		CreatedAt: deletedAt,
+		UpdatedAt: deletedAt,
+		DeletedAt: &deletedAt,
+		Version:   7,
+	}
+	if err := prepareCreate("alice", item); err != nil {
+		t.Fatalf("prepareCreate: %v", err)
+	}
+	want := domain.Item{Title: &title, UserID: "alice", Version: 1}
+	if *item != want {
+		t.Errorf("prepareCreate left %+v, want %+v", *item, want)
+	}
+
+	other := &domain.Item{Title: &title, UserID: "bob"}
+	if err := prepareCreate("alice", other); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("prepareCreate for another user returned %v, want %v", err, domain.ErrForbidden)
+	}
+}
+
+func TestOwnership(t *testing.T) {
+	title := "taken"
+	tests := []struct {
+		name string
+		call func(s *ItemService, ctx context.Context, id int64) error
+	}{
+		{"GetByID", func(s *ItemService, ctx context.Context, id int64) error {
+			_, err := s.GetByID(ctx, id)
+			return err
+		}},
+		{"Replace", func(s *ItemService, ctx context.Context, id int64) error {
+			return s.Replace(ctx, id, 0, &domain.Item{Title: &title})
+		}},
+		{"Patch", func(s *ItemService, ctx context.Context, id int64) error {
+			_, err := s.Patch(ctx, id, 0, domain.PatchTypeMerge, []byte(`{"title":"taken"}`))
+			return err
+		}},
+		{"Delete", func(s *ItemService, ctx context.Context, id int64) error {
+			return s.Delete(ctx, id, 0)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService()
+			item := createItem(t, s, alice, "mine")
+
+			if err := tt.call(s, as(bob), item.ID); !errors.Is(err, domain.ErrItemNotOwned) {
+				t.Errorf("%s of another user's item returned %v, want ErrItemNotOwned", tt.name, err)
+			}
+			if err := tt.call(s, as(admin), item.ID); !errors.Is(err, domain.ErrItemNotOwned) {
+				t.Errorf("%s of another user's item by an admin returned %v, want ErrItemNotOwned", tt.name, err)
+			}
+			if err := tt.call(s, as(bob), item.ID+1); !errors.Is(err, domain.ErrItemNotFound) {
+				t.Errorf("%s of a missing item returned %v, want ErrItemNotFound", tt.name, err)
+			}
+			if err := tt.call(s, context.Background(), item.ID); !errors.Is(err, domain.ErrUnauthenticated) {
+				t.Errorf("%s without a caller returned %v, want ErrUnauthenticated", tt.name, err)
+			}
+
+			got, err := s.GetByID(as(alice), item.ID)
+			if err != nil || *got.Title != "mine" || got.Version != item.Version {
+				t.Errorf("owner sees %+v, %v after the attempts, want the item unchanged", got, err)
+			}
+			if err := tt.call(s, as(alice), item.ID); err != nil {
+				t.Errorf("%s by the owner: %v", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestListingOtherUsersRequiresAdmin(t *testing.T) {
+	s := newTestService()
+	createItem(t, s, alice, "alice's")
+	createItem(t, s, bob, "bob's")
+	scoped := &domain.User{ID: "ops", Scopes: []string{domain.ScopeItemsAdmin}}
+
+	tests := []struct {
+		name      string
+		caller    *domain.User
+		list      func(ctx context.Context) (*domain.ItemPage, error)
+		wantErr   error
+		wantItems int
+	}{
+		{"all items as a user", alice, func(ctx context.Context) (*domain.ItemPage, error) {
+			return s.GetAll(ctx, domain.ItemQuery{})
+		}, domain.ErrForbidden, 0},
+		{"all items as an admin", admin, func(ctx context.Context) (*domain.ItemPage, error) {
+			return s.GetAll(ctx, domain.ItemQuery{})
+		}, nil, 2},
+		{"all items with the admin scope", scoped, func(ctx context.Context) (*domain.ItemPage, error) {
+			return s.GetAll(ctx, domain.ItemQuery{})
+		}, nil, 2},
+		{"all items of a user as an admin", admin, func(ctx context.Context) (*domain.ItemPage, error) {
+			return s.GetAll(ctx, domain.ItemQuery{ItemFilter: domain.ItemFilter{UserID: "bob"}})
+		}, nil, 1},
+		{"own items", alice, func(ctx context.Context) (*domain.ItemPage, error) {
+			return s.GetByUserID(ctx, "alice", domain.ItemQuery{})
+		}, nil, 1},
+		{"items of another user", alice, func(ctx context.Context) (*domain.ItemPage, error) {
+			return s.GetByUserID(ctx, "bob", domain.ItemQuery{})
+		}, domain.ErrForbidden, 0},
+		{"items of another user as an admin", admin, func(ctx context.Context) (*domain.ItemPage, error) {
+			return s.GetByUserID(ctx, "bob", domain.ItemQuery{})
+		}, nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := tt.list(as(tt.caller))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("listing returned %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && len(page.Items) != tt.wantItems {
+				t.Errorf("listing returned %d items, want %d", len(page.Items), tt.wantItems)
+			}
+		})
+	}
+
+	if _, err := s.GetAll(context.Background(), domain.ItemQuery{}); !errors.Is(err, domain.ErrUnauthenticated) {
+		t.Errorf("GetAll without a caller returned %v, want ErrUnauthenticated", err)
+	}
+}
+
+func TestTrash(t *testing.T) {
+	s := newTestService()
+	item := createItem(t, s, alice, "trashed")
+
+	if err := s.Delete(as(alice), item.ID, item.Version); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.GetByID(as(alice), item.ID); !errors.Is(err, domain.ErrItemNotFound) {
+		t.Errorf("GetByID of a trashed item returned %v, want ErrItemNotFound", err)
+	}
+	if err := s.Delete(as(alice), item.ID, 0); !errors.Is(err, domain.ErrItemNotFound) {
+		t.Errorf("Delete of a trashed item returned %v, want ErrItemNotFound", err)
+	}
+
+	if _, err := s.GetDeleted(as(alice), domain.ItemQuery{}); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("GetDeleted as a user returned %v, want ErrForbidden", err)
+	}
+	page, err := s.GetDeleted(as(admin), domain.ItemQuery{})
+	if err != nil || len(page.Items) != 1 || !page.Items[0].IsDeleted() {
+		t.Errorf("GetDeleted as an admin returned %+v, %v, want the trashed item", page, err)
+	}
+
+	if _, err := s.Restore(as(bob), item.ID); !errors.Is(err, domain.ErrItemNotFound) {
+		t.Errorf("Restore of another user's item returned %v, want ErrItemNotFound", err)
+	}
+	restored, err := s.Restore(as(alice), item.ID)
+	if err != nil || restored.IsDeleted() {
+		t.Fatalf("Restore returned %+v, %v, want the item out of the trash", restored, err)
+	}
+	if _, err := s.GetByID(as(alice), item.ID); err != nil {
+		t.Errorf("GetByID of a restored item: %v", err)
+	}
+	if _, err := s.Restore(as(alice), item.ID); !errors.Is(err, domain.ErrItemNotFound) {
+		t.Errorf("Restore of an item out of the trash returned %v, want ErrItemNotFound", err)
+	}
+}
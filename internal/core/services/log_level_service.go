@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+	"github.com/krisadabig/supreme-ms-item/internal/utils/contextutils"
+)
+
+// LogLevelService lets admins change the log level for a limited time, after
+// which the configured level is restored.
+type LogLevelService struct {
+	level  ports.LogLevelController
+	logger ports.Logger
+
+	mu         sync.Mutex
+	configured string
+	expiresAt  *time.Time
+	revert     *time.Timer
+	// generation identifies the latest override, so a timer firing while a
+	// newer override is being set does not end it.
+	generation int
+}
+
+func NewLogLevelService(level ports.LogLevelController, logger ports.Logger) *LogLevelService {
+	return &LogLevelService{
+		level:      level,
+		logger:     logger,
+		configured: level.Level(),
+	}
+}
+
+// SetConfigured changes the level to restore once a temporary change
+// expires, and applies it right away when none is in effect.
+func (s *LogLevelService) SetConfigured(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.expiresAt == nil {
+		if err := s.level.SetLevel(name); err != nil {
+			return fmt.Errorf("%w: %w", domain.ErrInvalidLogLevel, err)
+		}
+	}
+	s.configured = name
+	return nil
+}
+
+// Status reports the current level. Only admins may read it.
+func (s *LogLevelService) Status(ctx context.Context) (*domain.LogLevelStatus, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status(), nil
+}
+
+// Override switches to level for duration, DefaultLogLevelDuration when
+// zero. Only admins may change the level.
+func (s *LogLevelService) Override(ctx context.Context, level string, duration time.Duration) (*domain.LogLevelStatus, error) {
+	log := s.logger.WithContext(ctx).With("operation", "override_log_level")
+
+	if err := s.authorize(ctx); err != nil {
+		log.Warn("refusing to change log level for non-admin")
+		return nil, err
+	}
+
+	if duration == 0 {
+		duration = domain.DefaultLogLevelDuration
+	}
+	if duration < 0 || duration > domain.MaxLogLevelDuration {
+		return nil, fmt.Errorf("%w: duration must be between 0 and %s", domain.ErrInvalidLogLevel, domain.MaxLogLevelDuration)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := s.level.Level()
+	if err := s.level.SetLevel(level); err != nil {
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidLogLevel, err)
+	}
+
+	expiresAt := time.Now().Add(duration).UTC()
+	s.expiresAt = &expiresAt
+	if s.revert != nil {
+		s.revert.Stop()
+	}
+	s.generation++
+	generation := s.generation
+	s.revert = time.AfterFunc(duration, func() { s.restore(generation) })
+
+	// Logged at warn so the change is recorded whatever the new level.
+	log.With("caller_id", contextutils.UserIDFromContext(ctx)).
+		With("previous", previous).
+		With("level", level).
+		With("expires_at", expiresAt).
+		Warn("log level changed temporarily")
+	return s.status(), nil
+}
+
+// restore goes back to the configured level once an override expires.
+func (s *LogLevelService) restore(generation int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if generation != s.generation {
+		return
+	}
+	s.expiresAt, s.revert = nil, nil
+	if err := s.level.SetLevel(s.configured); err != nil {
+		s.logger.Error("failed to restore log level", err)
+		return
+	}
+	s.logger.With("level", s.configured).Warn("temporary log level expired")
+}
+
+// status must be called with s.mu held.
+func (s *LogLevelService) status() *domain.LogLevelStatus {
+	status := &domain.LogLevelStatus{
+		Level:      s.level.Level(),
+		Configured: s.configured,
+	}
+	if s.expiresAt != nil {
+		expiresAt := *s.expiresAt
+		status.ExpiresAt = &expiresAt
+	}
+	return status
+}
+
+func (s *LogLevelService) authorize(ctx context.Context) error {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !caller.IsAdmin() {
+		return domain.ErrForbidden
+	}
+	return nil
+}
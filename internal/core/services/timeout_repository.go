@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+// timeoutRepository bounds every call to the wrapped repository by a
+// deadline, so a slow query is cancelled instead of holding the request.
+type timeoutRepository struct {
+	repo    ports.ItemRepository
+	timeout time.Duration
+}
+
+func (r *timeoutRepository) Create(ctx context.Context, item *domain.Item) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.repo.Create(ctx, item)
+}
+
+func (r *timeoutRepository) CreateMany(ctx context.Context, items []*domain.Item) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.repo.CreateMany(ctx, items)
+}
+
+func (r *timeoutRepository) Update(ctx context.Context, item *domain.Item, fields []string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.repo.Update(ctx, item, fields)
+}
+
+func (r *timeoutRepository) UpdateMany(ctx context.Context, items []*domain.Item, fields []string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.repo.UpdateMany(ctx, items, fields)
+}
+
+func (r *timeoutRepository) Delete(ctx context.Context, userID string, id int64, expectedVersion int64) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.repo.Delete(ctx, userID, id, expectedVersion)
+}
+
+func (r *timeoutRepository) DeleteMany(ctx context.Context, userID string, refs []domain.ItemRef) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.repo.DeleteMany(ctx, userID, refs)
+}
+
+func (r *timeoutRepository) Restore(ctx context.Context, userID string, id int64) (*domain.Item, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.repo.Restore(ctx, userID, id)
+}
+
+func (r *timeoutRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.repo.Purge(ctx, deletedBefore)
+}
+
+func (r *timeoutRepository) GetAll(ctx context.Context, query domain.ItemQuery) (*domain.ItemPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.repo.GetAll(ctx, query)
+}
+
+func (r *timeoutRepository) GetByID(ctx context.Context, userID string, id int64) (*domain.Item, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.repo.GetByID(ctx, userID, id)
+}
+
+func (r *timeoutRepository) GetByUserID(ctx context.Context, userID string, query domain.ItemQuery) (*domain.ItemPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.repo.GetByUserID(ctx, userID, query)
+}
+
+func (r *timeoutRepository) GetDeleted(ctx context.Context, query domain.ItemQuery) (*domain.ItemPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.repo.GetDeleted(ctx, query)
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/logger"
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/storage/memory"
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+// stuckRepository blocks in GetByID until ctx is done and reports it the
+// way the adapters do. It records whether ctx had a deadline.
+type stuckRepository struct {
+	ports.ItemRepository
+	hadDeadline bool
+}
+
+func (r *stuckRepository) GetByID(ctx context.Context, userID string, id int64) (*domain.Item, error) {
+	_, r.hadDeadline = ctx.Deadline()
+	<-ctx.Done()
+	return nil, fmt.Errorf("%w: %w", domain.ErrTimeout, ctx.Err())
+}
+
+func TestQueryTimeout(t *testing.T) {
+	repo := &stuckRepository{ItemRepository: memory.NewMemoryItemRepository()}
+	s := NewItemService(repo, logger.New(logger.WithOutput(io.Discard)), WithQueryTimeout(20*time.Millisecond))
+
+	start := time.Now()
+	_, err := s.GetByID(as(alice), 1)
+	if !errors.Is(err, domain.ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetByID returned %v, want a timeout", err)
+	}
+	if !repo.hadDeadline {
+		t.Error("repository call ran without a deadline")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("GetByID took %v", elapsed)
+	}
+}
+
+func TestQueryTimeoutDisabled(t *testing.T) {
+	repo := &stuckRepository{ItemRepository: memory.NewMemoryItemRepository()}
+	s := NewItemService(repo, logger.New(logger.WithOutput(io.Discard)), WithQueryTimeout(0))
+
+	// The caller's cancellation still reaches the repository.
+	ctx, cancel := context.WithCancel(as(alice))
+	cancel()
+	if _, err := s.GetByID(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetByID returned %v, want the cancellation", err)
+	}
+	if repo.hadDeadline {
+		t.Error("repository call ran with a deadline although the timeout is disabled")
+	}
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+)
+
+const (
+	// txAttempts bounds how many times a transaction that lost to a
+	// concurrent one is run.
+	txAttempts = 3
+	// txRetryDelay is the pause before the first retry, growing linearly
+	// with every further attempt.
+	txRetryDelay = 20 * time.Millisecond
+)
+
+// noopTxManager runs functions without a transaction, for repositories
+// whose single calls are atomic enough.
+type noopTxManager struct{}
+
+func (noopTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// inTx runs fn in a transaction and runs it again, after a short pause, while
+// it fails with domain.ErrTxConflict. fn must only change state through the
+// repository and ctx must not carry a transaction already, as a conflict
+// aborts the outermost one.
+func (s *ItemService) inTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		err := s.tx.WithinTx(ctx, fn)
+		if err == nil || !errors.Is(err, domain.ErrTxConflict) || attempt == txAttempts {
+			return err
+		}
+
+		s.logger.WithContext(ctx).
+			With("attempt", attempt).
+			With("error", err.Error()).
+			Warn("transaction conflict, retrying")
+
+		timer := time.NewTimer(time.Duration(attempt) * txRetryDelay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/logger"
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
+)
+
+// conflictingTxManager fails its first conflicts calls with a transaction
+// conflict and runs fn without a transaction afterwards.
+type conflictingTxManager struct {
+	conflicts int
+	calls     int
+}
+
+func (m *conflictingTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	if m.calls <= m.conflicts {
+		return fmt.Errorf("%w: %w: could not serialize access", domain.ErrUnavailable, domain.ErrTxConflict)
+	}
+	return fn(ctx)
+}
+
+func TestInTx(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name      string
+		conflicts int
+		fnErr     error
+		wantErr   error
+		wantCalls int
+		wantRuns  int
+	}{
+		{"success", 0, nil, nil, 1, 1},
+		{"error of fn is not retried", 0, errFailed, errFailed, 1, 1},
+		{"conflicts are retried", txAttempts - 1, nil, nil, txAttempts, 1},
+		{"gives up after txAttempts", txAttempts + 1, nil, domain.ErrTxConflict, txAttempts, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := &conflictingTxManager{conflicts: tt.conflicts}
+			s := NewItemService(nil, logger.New(logger.WithOutput(io.Discard)), WithTxManager(tm))
+
+			runs := 0
+			err := s.inTx(context.Background(), func(context.Context) error {
+				runs++
+				return tt.fnErr
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("inTx returned %v, want %v", err, tt.wantErr)
+			}
+			if tm.calls != tt.wantCalls || runs != tt.wantRuns {
+				t.Errorf("inTx started %d transactions running fn %d times, want %d and %d",
+					tm.calls, runs, tt.wantCalls, tt.wantRuns)
+			}
+		})
+	}
+}
+
+func TestInTxStopsWhenContextIsDone(t *testing.T) {
+	tm := &conflictingTxManager{conflicts: txAttempts}
+	s := NewItemService(nil, logger.New(logger.WithOutput(io.Discard)), WithTxManager(tm))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := s.inTx(ctx, func(context.Context) error { return nil })
+	if !errors.Is(err, domain.ErrTxConflict) || tm.calls != 1 {
+		t.Errorf("inTx returned %v after %d transactions, want the conflict after 1", err, tm.calls)
+	}
+}
@@ -0,0 +1,155 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/krisadabig/supreme-ms-item/internal/core/ports"
+)
+
+// Hook is a component with start and stop callbacks. OnStart must not block;
+// long running work belongs in a goroutine (see Lifecycle.Go). Either
+// callback may be nil.
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Lifecycle starts hooks in registration order, waits for SIGINT/SIGTERM or
+// a shutdown request, and stops the started hooks in reverse order within
+// the shutdown timeout.
+type Lifecycle struct {
+	logger          ports.Logger
+	shutdownTimeout time.Duration
+
+	hooks    []Hook
+	shutdown chan error
+	once     sync.Once
+}
+
+func New(logger ports.Logger, shutdownTimeout time.Duration) *Lifecycle {
+	return &Lifecycle{
+		logger:          logger.With("component", "lifecycle"),
+		shutdownTimeout: shutdownTimeout,
+		shutdown:        make(chan error, 1),
+	}
+}
+
+// Append registers a hook.
+func (l *Lifecycle) Append(hook Hook) {
+	l.hooks = append(l.hooks, hook)
+}
+
+// Go registers a background worker. run receives a context that is
+// cancelled on shutdown; stopping waits for run to return. A worker that
+// fails with an error other than context.Canceled shuts the application down.
+func (l *Lifecycle) Go(name string, run func(ctx context.Context) error) {
+	var (
+		cancel context.CancelFunc
+		done   chan struct{}
+	)
+	l.Append(Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			done = make(chan struct{})
+			go func() {
+				defer close(done)
+				if err := run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+					l.logger.With("hook", name).Error("background worker failed", err)
+					l.Shutdown(err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+}
+
+// Shutdown asks Run to stop the application. A non-nil err is returned by
+// Run. Only the first request is honoured.
+func (l *Lifecycle) Shutdown(err error) {
+	l.once.Do(func() {
+		l.shutdown <- err
+	})
+}
+
+// Run starts every hook and blocks until ctx is done, a termination signal
+// arrives or Shutdown is called, then stops the hooks.
+func (l *Lifecycle) Run(ctx context.Context) error {
+	ctx, stopSignals := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	started, err := l.start(ctx)
+	if err != nil {
+		l.stop(started)
+		return err
+	}
+	l.logger.Info("application started")
+
+	var cause error
+	select {
+	case <-ctx.Done():
+		l.logger.Info("termination signal received, shutting down")
+	case cause = <-l.shutdown:
+		l.logger.Info("shutdown requested")
+	}
+
+	if err := l.stop(started); err != nil && cause == nil {
+		cause = err
+	}
+	return cause
+}
+
+// start runs the OnStart callbacks and returns the hooks that started.
+func (l *Lifecycle) start(ctx context.Context) ([]Hook, error) {
+	started := make([]Hook, 0, len(l.hooks))
+	for _, hook := range l.hooks {
+		if hook.OnStart != nil {
+			l.logger.With("hook", hook.Name).Debug("starting")
+			if err := hook.OnStart(ctx); err != nil {
+				l.logger.With("hook", hook.Name).Error("failed to start", err)
+				return started, err
+			}
+		}
+		started = append(started, hook)
+	}
+	return started, nil
+}
+
+// stop runs the OnStop callbacks in reverse order sharing one deadline.
+func (l *Lifecycle) stop(started []Hook) error {
+	ctx, cancel := context.WithTimeout(context.Background(), l.shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		hook := started[i]
+		if hook.OnStop == nil {
+			continue
+		}
+		log := l.logger.With("hook", hook.Name)
+		log.Debug("stopping")
+		if err := hook.OnStop(ctx); err != nil {
+			log.Error("failed to stop", err)
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,161 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/krisadabig/supreme-ms-item/internal/adapters/secondary/logger"
+)
+
+// recorder collects the order in which hooks are called.
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) hook(name string, startErr, stopErr error) Hook {
+	return Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			r.record("start " + name)
+			return startErr
+		},
+		OnStop: func(context.Context) error {
+			r.record("stop " + name)
+			return stopErr
+		},
+	}
+}
+
+func (r *recorder) record(call string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+func newLifecycle(timeout time.Duration) *Lifecycle {
+	return New(logger.New(logger.WithOutput(io.Discard)), timeout)
+}
+
+func TestRunStopsHooksInReverseOrder(t *testing.T) {
+	var r recorder
+	l := newLifecycle(time.Second)
+	l.Append(r.hook("storage", nil, nil))
+	l.Append(r.hook("server", nil, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l.Append(Hook{Name: "trigger", OnStart: func(context.Context) error {
+		cancel()
+		return nil
+	}})
+
+	if err := l.Run(ctx); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := []string{"start storage", "start server", "stop server", "stop storage"}
+	if !slices.Equal(r.calls, want) {
+		t.Errorf("calls %v, want %v", r.calls, want)
+	}
+}
+
+func TestRunStopsStartedHooksWhenOneFailsToStart(t *testing.T) {
+	var r recorder
+	errStart := errors.New("port in use")
+	l := newLifecycle(time.Second)
+	l.Append(r.hook("storage", nil, nil))
+	l.Append(r.hook("server", errStart, nil))
+	l.Append(r.hook("worker", nil, nil))
+
+	if err := l.Run(context.Background()); !errors.Is(err, errStart) {
+		t.Fatalf("Run returned %v, want %v", err, errStart)
+	}
+	want := []string{"start storage", "start server", "stop storage"}
+	if !slices.Equal(r.calls, want) {
+		t.Errorf("calls %v, want %v", r.calls, want)
+	}
+}
+
+func TestShutdownReturnsItsCause(t *testing.T) {
+	var r recorder
+	errFatal := errors.New("listener closed")
+	errStop := errors.New("flush failed")
+	l := newLifecycle(time.Second)
+	l.Append(r.hook("storage", nil, errStop))
+	l.Append(Hook{Name: "server", OnStart: func(context.Context) error {
+		l.Shutdown(errFatal)
+		l.Shutdown(errors.New("ignored"))
+		return nil
+	}})
+
+	if err := l.Run(context.Background()); !errors.Is(err, errFatal) {
+		t.Errorf("Run returned %v, want %v", err, errFatal)
+	}
+
+	// Without a cause, stop errors are returned.
+	l = newLifecycle(time.Second)
+	l.Append(r.hook("storage", nil, errStop))
+	l.Append(Hook{Name: "server", OnStart: func(context.Context) error {
+		l.Shutdown(nil)
+		return nil
+	}})
+	if err := l.Run(context.Background()); !errors.Is(err, errStop) {
+		t.Errorf("Run returned %v, want %v", err, errStop)
+	}
+}
+
+func TestGo(t *testing.T) {
+	l := newLifecycle(time.Second)
+	stopped := make(chan struct{})
+	l.Go("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		close(stopped)
+		return ctx.Err()
+	})
+	l.Append(Hook{Name: "trigger", OnStart: func(context.Context) error {
+		l.Shutdown(nil)
+		return nil
+	}})
+	if err := l.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	select {
+	case <-stopped:
+	default:
+		t.Error("Run returned before the worker stopped")
+	}
+
+	// A failing worker shuts the application down.
+	errWorker := errors.New("purge failed")
+	l = newLifecycle(time.Second)
+	l.Go("worker", func(context.Context) error { return errWorker })
+	if err := l.Run(context.Background()); !errors.Is(err, errWorker) {
+		t.Errorf("Run returned %v, want %v", err, errWorker)
+	}
+}
+
+func TestStopHonoursShutdownTimeout(t *testing.T) {
+	l := newLifecycle(50 * time.Millisecond)
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	l.Go("stuck", func(context.Context) error {
+		<-release
+		return nil
+	})
+	l.Append(Hook{Name: "trigger", OnStart: func(context.Context) error {
+		l.Shutdown(nil)
+		return nil
+	}})
+
+	start := time.Now()
+	if err := l.Run(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run returned %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Run took %v to give up on a stuck worker", elapsed)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/krisadabig/supreme-ms-item/internal/constants"
+	"github.com/krisadabig/supreme-ms-item/internal/core/domain"
 )
 
 // ContextWithRequestID stores a request ID in the context for downstream logging.
@@ -27,3 +28,31 @@ func RequestIDFromContext(ctx context.Context) string {
 	}
 	return ""
 }
+
+// ContextWithUser stores the authenticated user in the context.
+func ContextWithUser(ctx context.Context, user *domain.User) context.Context {
+	if user == nil {
+		return ctx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, constants.ContextKeyUser, user)
+}
+
+// UserFromContext retrieves the authenticated user from the context if available.
+func UserFromContext(ctx context.Context) (*domain.User, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	user, ok := ctx.Value(constants.ContextKeyUser).(*domain.User)
+	return user, ok && user != nil
+}
+
+// UserIDFromContext retrieves the authenticated user ID from the context if available.
+func UserIDFromContext(ctx context.Context) string {
+	if user, ok := UserFromContext(ctx); ok {
+		return user.ID
+	}
+	return ""
+}